@@ -0,0 +1,15 @@
+data "ksqldb_health" "this" {}
+
+resource "ksqldb_stream" "orders" {
+  name         = "ORDERS"
+  kafka_topic  = "orders"
+  key_format   = "KAFKA"
+  value_format = "AVRO"
+
+  lifecycle {
+    precondition {
+      condition     = data.ksqldb_health.this.is_healthy
+      error_message = "The ksqlDB server is not healthy."
+    }
+  }
+}
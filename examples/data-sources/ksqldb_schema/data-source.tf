@@ -0,0 +1,11 @@
+data "ksqldb_schema" "orders_value" {
+  subject = "orders-value"
+}
+
+resource "ksqldb_stream" "orders" {
+  name            = "ORDERS"
+  kafka_topic     = "orders"
+  key_format      = "KAFKA"
+  value_format    = "AVRO"
+  value_schema_id = data.ksqldb_schema.orders_value.id
+}
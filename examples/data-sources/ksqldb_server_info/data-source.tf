@@ -0,0 +1,8 @@
+data "ksqldb_server_info" "this" {
+  lifecycle {
+    postcondition {
+      condition     = split(".", self.version)[0] == "0" && tonumber(split(".", self.version)[1]) >= 24
+      error_message = "The streams in this module require ksqlDB 0.24 or later."
+    }
+  }
+}
@@ -0,0 +1,3 @@
+# Streams can be imported by their name. Names which are not enclosed by backticks are converted to uppercase.
+terraform import ksqldb_stream.input INPUT
+terraform import ksqldb_stream.orders '`orders-v2`'
@@ -0,0 +1,94 @@
+package ksqldb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strings"
+	"terraform-provider-ksqldb/internal/ksqldb/parser"
+	"terraform-provider-ksqldb/internal/ksqldb/util"
+)
+
+// adoptStream returns the existing stream instead of creating it, if its definition matches the configuration.
+func (c *Client) adoptStream(ctx context.Context, data StreamResourceModel, existing *Source) (*Source, error) {
+
+	differences, err := streamDifferences(c.defaults.apply(data), existing)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(differences) > 0 {
+		return nil, fmt.Errorf("there is already a stream or a table named %s which differs from the configuration:\n  %s",
+			data.Name.ValueString(), strings.Join(differences, "\n  "))
+	}
+
+	tflog.Info(ctx, "Adopting existing stream", map[string]interface{}{"name": existing.Name})
+
+	return existing, nil
+}
+
+// streamDifferences compares the planned stream with an existing one and describes each attribute which differs,
+// e.g. `kafka_topic: configured "orders", existing "orders_v1"`. Attributes which are computed by ksqlDB are only
+// compared if they are configured.
+func streamDifferences(planned StreamResourceModel, existing *Source) ([]string, error) {
+
+	if existing.Type != "STREAM" {
+		return []string{fmt.Sprintf("type: configured STREAM, existing %s", existing.Type)}, nil
+	}
+
+	// the query is kept if it is equivalent to the existing one, so that formatting doesn't count as a difference
+	actual := StreamResourceModel{Name: planned.Name, Query: planned.Query}
+	if err := setFromSource(&actual, existing); err != nil {
+		return nil, err
+	}
+
+	var differences []string
+	differ := func(attribute string, configured attr.Value, existing attr.Value) {
+		differences = append(differences, fmt.Sprintf("%s: configured %s, existing %s", attribute, configured, existing))
+	}
+
+	compareConfigured := func(attribute string, configured attr.Value, existing attr.Value, equal bool) {
+		if !configured.IsNull() && !configured.IsUnknown() && !equal {
+			differ(attribute, configured, existing)
+		}
+	}
+
+	compare := func(attribute string, configured attr.Value, existing attr.Value) {
+		if !configured.IsUnknown() && !configured.Equal(existing) {
+			differ(attribute, configured, existing)
+		}
+	}
+
+	compare("kafka_topic", planned.KafkaTopic, actual.KafkaTopic)
+	compareConfigured("key_format", planned.KeyFormat, actual.KeyFormat,
+		strings.EqualFold(planned.KeyFormat.ValueString(), actual.KeyFormat.ValueString()))
+	compareConfigured("value_format", planned.ValueFormat, actual.ValueFormat,
+		strings.EqualFold(planned.ValueFormat.ValueString(), actual.ValueFormat.ValueString()))
+	compareConfigured("partitions", planned.Partitions, actual.Partitions, planned.Partitions.Equal(actual.Partitions))
+	compareConfigured("replicas", planned.Replicas, actual.Replicas, planned.Replicas.Equal(actual.Replicas))
+	compare("key_schema_id", planned.KeySchemaId, actual.KeySchemaId)
+	compare("value_schema_id", planned.ValueSchemaId, actual.ValueSchemaId)
+	compare("retention_ms", planned.Retention, actual.Retention)
+	compare("timestamp_format", planned.TimestampFormat, actual.TimestampFormat)
+	compare("source", types.BoolValue(planned.Source.ValueBool()), actual.Source)
+	compare("query", planned.Query, actual.Query)
+
+	// the configuration can't declare columns, they are inferred from the schema IDs or from the query, so the
+	// columns of a stream which declares them can't be compared
+	statement, err := parser.Parse(existing.Statement)
+	if err != nil {
+		return nil, err
+	}
+	if statement.Columns != "" {
+		differences = append(differences, fmt.Sprintf("columns: inferred from the configuration, existing (%s)", statement.Columns))
+	}
+
+	if !planned.Timestamp.IsUnknown() &&
+		util.NormalizeIdentifier(planned.Timestamp.ValueString()) != util.NormalizeIdentifier(actual.Timestamp.ValueString()) {
+		differ("timestamp", planned.Timestamp, actual.Timestamp)
+	}
+
+	return differences, nil
+}
@@ -0,0 +1,97 @@
+package ksqldb
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"testing"
+)
+
+func TestStreamDifferences(t *testing.T) {
+	server := testAccFakeServer(t)
+	for _, ksql := range []string{
+		"CREATE STREAM ORDERS WITH (KAFKA_TOPIC='orders', KEY_FORMAT='AVRO', VALUE_FORMAT='AVRO', PARTITIONS=3, TIMESTAMP='CREATED_AT');",
+		"CREATE STREAM LARGE_ORDERS AS SELECT * FROM ORDERS WHERE AMOUNT > 100 EMIT CHANGES;",
+		"CREATE TABLE TOTALS AS SELECT ID, SUM(AMOUNT) AS TOTAL FROM ORDERS GROUP BY ID EMIT CHANGES;",
+		"CREATE STREAM PAYMENTS (ID INT KEY, AMOUNT DOUBLE) WITH (KAFKA_TOPIC='payments', KEY_FORMAT='KAFKA', VALUE_FORMAT='JSON');",
+	} {
+		if err := server.Exec(ksql); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := NewClusterClient([]string{server.URL}, "", "")
+
+	orders := emptyStream("ORDERS")
+	orders.KafkaTopic = types.StringValue("orders")
+	orders.KeyFormat = types.StringValue("AVRO")
+	orders.ValueFormat = types.StringValue("AVRO")
+	orders.Timestamp = types.StringValue("created_at")
+
+	largeOrders := emptyStream("LARGE_ORDERS")
+	largeOrders.KafkaTopic = types.StringValue("LARGE_ORDERS")
+	largeOrders.Query = types.StringValue("select *\n  from orders where amount > 100")
+
+	payments := emptyStream("PAYMENTS")
+	payments.KafkaTopic = types.StringValue("payments")
+	payments.KeyFormat = types.StringValue("KAFKA")
+	payments.ValueFormat = types.StringValue("JSON")
+
+	tests := []struct {
+		name    string
+		planned StreamResourceModel
+		change  func(plan *StreamResourceModel)
+		want    []string
+	}{
+		{"identical", orders, func(p *StreamResourceModel) {}, nil},
+		{"format in lowercase", orders, func(p *StreamResourceModel) { p.ValueFormat = types.StringValue("avro") }, nil},
+		{"unknown and computed attributes", orders, func(p *StreamResourceModel) {
+			p.KafkaTopic = types.StringUnknown()
+			p.KeyFormat = types.StringNull()
+			p.Partitions = types.Int64Unknown()
+		}, nil},
+		{"configured partitions", orders, func(p *StreamResourceModel) { p.Partitions = types.Int64Value(3) }, nil},
+		{"differences", orders, func(p *StreamResourceModel) {
+			p.KafkaTopic = types.StringValue("orders_v2")
+			p.ValueFormat = types.StringValue("JSON")
+			p.Partitions = types.Int64Value(6)
+			p.Timestamp = types.StringNull()
+		}, []string{
+			`kafka_topic: configured "orders_v2", existing "orders"`,
+			`value_format: configured "JSON", existing "AVRO"`,
+			`partitions: configured 6, existing 3`,
+			`timestamp: configured <null>, existing "CREATED_AT"`,
+		}},
+		{"source stream", orders, func(p *StreamResourceModel) { p.Source = types.BoolValue(true) }, []string{`source: configured true, existing false`}},
+		{"equivalent query", largeOrders, func(p *StreamResourceModel) {}, nil},
+		{"different query", largeOrders, func(p *StreamResourceModel) { p.Query = types.StringValue("SELECT * FROM ORDERS") }, []string{
+			`query: configured "SELECT * FROM ORDERS", existing "SELECT * FROM ORDERS WHERE AMOUNT > 100 EMIT CHANGES"`,
+		}},
+		{"missing query", largeOrders, func(p *StreamResourceModel) { p.Query = types.StringNull() }, []string{
+			`query: configured <null>, existing "SELECT * FROM ORDERS WHERE AMOUNT > 100 EMIT CHANGES"`,
+		}},
+		// only the columns differ, the stream would be created with the columns inferred from the topic's schema
+		{"declared columns", payments, func(p *StreamResourceModel) {}, []string{
+			"columns: inferred from the configuration, existing (ID INT KEY, AMOUNT DOUBLE)",
+		}},
+		{"table", emptyStream("TOTALS"), func(p *StreamResourceModel) {}, []string{"type: configured STREAM, existing TABLE"}},
+	}
+
+	for _, test := range tests {
+		existing, err := client.describe(context.Background(), test.planned.Name.ValueString())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		planned := test.planned
+		test.change(&planned)
+
+		got, err := streamDifferences(planned, existing)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: expected %q, got %q", test.name, test.want, got)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package ksqldb
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -10,27 +11,103 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"terraform-provider-ksqldb/internal/ksqldb/util"
 	"time"
 )
 
 var mutex sync.Mutex
 
+const describeAttempts = 5
+
+// describeRetryInterval is the time between attempts to describe a created stream or table.
+var describeRetryInterval = time.Second
+
+// ReadBackError is returned if a statement was executed but the created object could not be described
+// afterwards. The object exists in ksqlDB.
+type ReadBackError struct {
+	Name string
+	Err  error
+}
+
+func (e *ReadBackError) Error() string {
+	return fmt.Sprintf("%s was created but could not be read back: %s", e.Name, e.Err)
+}
+
+func (e *ReadBackError) Unwrap() error {
+	return e.Err
+}
+
 // Client the ksqldb client object.
 type Client struct {
-	client   *http.Client
-	url      string
-	username string
-	password string
+	client         *http.Client
+	urls           []string
+	username       string
+	password       string
+	schemaRegistry *SchemaRegistryClient
+	defaults       StreamDefaults
+	naming         NamingConvention
+	// readOnly rejects every statement which changes anything in ksqlDB
+	readOnly bool
+	// migrations receives every statement which changes anything in ksqlDB, if configured
+	migrations        *MigrationWriter
+	executeMigrations bool
+	// headers and userAgent are added to every request, see setHeaders
+	headers   map[string]string
+	userAgent string
+	// logBodyLimit is the number of bytes of bodies which are logged, defaultLogBodyLimit if nil
+	logBodyLimit *int
+
+	// version is fetched on first use, see serverVersion
+	versionMutex sync.Mutex
+	version      *ServerVersion
+
+	// the node requests are sent to first, see nodes
+	nodeMutex     sync.Mutex
+	preferred     int
+	healthChecked bool
+
+	// the sequence number of the last command executed by this client, see doRequest
+	commandMutex          sync.Mutex
+	commandSequenceNumber *int64
 }
 
 type Response struct {
-	ErrorCode int    `json:"error_code"`
-	Message   string `json:"message"`
-	Source    Source `json:"sourceDescription"`
+	ErrorCode int                        `json:"error_code"`
+	Message   string                     `json:"message"`
+	Source    Source                     `json:"sourceDescription"`
+	Streams   []SourceSummary            `json:"streams"`
+	Tables    []SourceSummary            `json:"tables"`
+	Types     map[string]json.RawMessage `json:"types"`
+	Queries   []Query                    `json:"queries"`
+
+	CommandId             string        `json:"commandId"`
+	CommandStatus         CommandStatus `json:"commandStatus"`
+	CommandSequenceNumber *int64        `json:"commandSequenceNumber"`
+}
+
+type CommandStatus struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+type SourceSummary struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Topic       string `json:"topic"`
+	KeyFormat   string `json:"keyFormat"`
+	ValueFormat string `json:"valueFormat"`
+}
+
+type Query struct {
+	Id          string   `json:"id"`
+	QueryString string   `json:"queryString"`
+	QueryType   string   `json:"queryType"`
+	Sinks       []string `json:"sinks"`
 }
 
 type Source struct {
 	Name        string `json:"name"`
+	Type        string `json:"type"`
 	KeyFormat   string `json:"keyFormat"`
 	ValueFormat string `json:"valueFormat"`
 	Topic       string `json:"topic"`
@@ -40,58 +117,155 @@ type Source struct {
 	Timestamp   string `json:"timestamp"`
 }
 
+type ServerInfo struct {
+	Version        string `json:"version"`
+	KafkaClusterId string `json:"kafkaClusterId"`
+	KsqlServiceId  string `json:"ksqlServiceId"`
+	ServerStatus   string `json:"serverStatus"`
+}
+
+type HealthCheck struct {
+	IsHealthy bool                         `json:"isHealthy"`
+	Details   map[string]HealthCheckDetail `json:"details"`
+}
+
+type HealthCheckDetail struct {
+	IsHealthy bool `json:"isHealthy"`
+}
+
+type HostStatus struct {
+	HostAlive          bool  `json:"hostAlive"`
+	LastStatusUpdateMs int64 `json:"lastStatusUpdateMs"`
+}
+
 type Payload struct {
 	Ksql       string            `json:"ksql"`
 	Properties map[string]string `json:"streamsProperties"`
+	// CommandSequenceNumber makes the server wait until it has executed the given command before it
+	// executes the statement.
+	CommandSequenceNumber *int64 `json:"commandSequenceNumber,omitempty"`
 }
 
-// NewClient creates a new ksqldb Client.
+// NewClient creates a new ksqldb Client. The url may be a comma separated list of the nodes of a ksqlDB cluster.
 func NewClient(url, username, password *string) *Client {
+	return NewClusterClient(splitUrls(*url), *username, *password)
+}
+
+// NewClusterClient creates a new ksqldb Client which fails over between the given nodes of a ksqlDB cluster.
+func NewClusterClient(urls []string, username, password string) *Client {
 	return &Client{
-		client:   &http.Client{Timeout: 10 * time.Second},
-		url:      *url,
-		username: *username,
-		password: *password,
+		client:   newHTTPClient(HTTPSettings{}),
+		urls:     urls,
+		username: username,
+		password: password,
 	}
 }
 
+// doRequest executes a statement. Every statement waits until the server has executed all commands sent by
+// this client before, so that e.g. a DESCRIBE after a CREATE finds the created stream on any node.
 func (c *Client) doRequest(ctx context.Context, payload *Payload) (*Response, error) {
 
-	rb, err := json.Marshal(payload)
+	if c.readOnly && !isReadOnlyStatement(payload.Ksql) {
+		return nil, fmt.Errorf("the provider is read-only, refusing to execute: %s", payload.Ksql)
+	}
+
+	migrate := c.migrations != nil && !isReadOnlyStatement(payload.Ksql)
+
+	if migrate && !c.executeMigrations {
+		if err := c.writeMigration(ctx, payload); err != nil {
+			return nil, err
+		}
+		return &Response{}, nil
+	}
+
+	if payload.CommandSequenceNumber == nil {
+		payload.CommandSequenceNumber = c.lastCommandSequenceNumber()
+	}
+
+	response, err := c.execute(ctx, payload)
 	if err != nil {
 		return nil, err
 	}
 
-	tflog.Info(ctx, fmt.Sprintf("Executing KSQL request: %s", rb))
+	if response.CommandSequenceNumber != nil {
+		c.commandExecuted(*response.CommandSequenceNumber)
+	}
+
+	if response.CommandId != "" && response.CommandStatus.Status != "" {
+		if err := c.waitForCommand(ctx, response.CommandId, response.CommandStatus); err != nil {
+			return nil, err
+		}
+	}
+
+	// only statements which succeeded are written, ksql-migrations would fail on the others
+	if migrate {
+		if err := c.writeMigration(ctx, payload); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// writeMigration writes the statement of the payload to the migrations.
+func (c *Client) writeMigration(ctx context.Context, payload *Payload) error {
+
+	file, err := c.migrations.write(payload.Ksql, payload.Properties)
+	if err != nil {
+		return fmt.Errorf("could not write migration: %w", err)
+	}
+
+	tflog.Info(ctx, "Wrote migration", map[string]interface{}{"file": file})
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/ksql", c.url), strings.NewReader(string(rb)))
+	return nil
+}
+
+func (c *Client) execute(ctx context.Context, payload *Payload) (*Response, error) {
+
+	rb, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
 
-	req.SetBasicAuth(c.username, c.password)
+	ctx = logRequest(ctx, payload)
 
-	// set headers according to ksqlDB HTTP API Reference: https://docs.ksqldb.io/en/latest/developer-guide/api/#content-types
-	req.Header.Set("Accept", "application/vnd.ksql.v1+json")
+	tflog.Info(ctx, "Executing KSQL statement")
+
+	if redacted, err := redactedBody(payload); err == nil {
+		tflog.Trace(ctx, "KSQL request body", map[string]interface{}{"body": c.truncateBody(redacted)})
+	}
+
+	newRequest := func(url string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/ksql", url), bytes.NewReader(rb))
+		if err != nil {
+			return nil, err
+		}
+
+		c.setHeaders(req)
+
+		return req, nil
+	}
 
 	// limit access to the client to one resource at a time. Otherwise, a ProducerFencedException may occur in ksqlDB.
 	mutex.Lock()
 
-	res, err := c.client.Do(req)
+	res, err := c.send(ctx, isReadOnlyStatement(payload.Ksql), newRequest)
+
+	// release
+	mutex.Unlock()
+
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	// release
-	mutex.Unlock()
-
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	tflog.Info(ctx, fmt.Sprintf("Received KSQL response: %s", string(body)))
+	tflog.Info(ctx, "Received KSQL response", map[string]interface{}{"status_code": res.StatusCode, "body_size": len(body)})
+	tflog.Debug(ctx, "KSQL response body", map[string]interface{}{"body": c.truncateBody(body)})
 
 	if res.StatusCode != http.StatusOK {
 		var obj Response
@@ -111,6 +285,10 @@ func (c *Client) doRequest(ctx context.Context, payload *Payload) (*Response, er
 			if err := json.Unmarshal(body, &obj); err != nil {
 				return nil, err
 			}
+			// statements like SET don't return any entities
+			if len(obj) == 0 {
+				return &Response{}, nil
+			}
 			return &obj[0], nil
 		case '{':
 			var obj Response
@@ -125,6 +303,69 @@ func (c *Client) doRequest(ctx context.Context, payload *Payload) (*Response, er
 	return nil, errors.New("response must be object or list")
 }
 
+// get sends a GET request to the given endpoint of the ksqlDB REST API and returns the status code and body.
+// Unlike statements, GET requests only read the server state and are therefore not serialized.
+func (c *Client) get(ctx context.Context, endpoint string) (int, []byte, error) {
+
+	ctx = maskCredentials(tflog.SetField(ctx, "endpoint", endpoint))
+
+	tflog.Info(ctx, "Fetching ksqlDB endpoint")
+
+	// GET requests don't change anything, so they can always be sent to another node
+	res, err := c.send(ctx, true, func(url string) (*http.Request, error) {
+		return c.newGetRequest(ctx, url, endpoint)
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	tflog.Info(ctx, "Received ksqlDB response", map[string]interface{}{"status_code": res.StatusCode, "body_size": len(body)})
+	tflog.Debug(ctx, "ksqlDB response body", map[string]interface{}{"body": c.truncateBody(body)})
+
+	return res.StatusCode, body, nil
+}
+
+// doGet sends a GET request to the given endpoint and decodes the JSON response into target.
+func (c *Client) doGet(ctx context.Context, endpoint string, target any) error {
+
+	status, body, err := c.get(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return responseError(endpoint, status, body)
+	}
+
+	return json.Unmarshal(body, target)
+}
+
+func (c *Client) newGetRequest(ctx context.Context, url string, endpoint string) (*http.Request, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s%s", url, endpoint), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.setHeaders(req)
+
+	return req, nil
+}
+
+func responseError(endpoint string, status int, body []byte) error {
+	var obj Response
+	if err := json.Unmarshal(body, &obj); err != nil || obj.Message == "" {
+		return fmt.Errorf("%s responded with status %d", endpoint, status)
+	}
+	return errors.New(obj.Message)
+}
+
 func (c *Client) describe(ctx context.Context, name string) (*Source, error) {
 
 	payload := Payload{
@@ -136,28 +377,98 @@ func (c *Client) describe(ctx context.Context, name string) (*Source, error) {
 		return nil, err
 	}
 
+	// e.g. an empty response, which must not be mistaken for an existing stream
+	if response.Source.Name == "" {
+		return nil, fmt.Errorf("ksqlDB returned no description of %s", name)
+	}
+
 	return &response.Source, nil
 }
 
-func (c *Client) createStream(ctx context.Context, data StreamResourceModel, materialized bool, source bool) (*Source, error) {
-	return c.doCreateStream(ctx, data, source, materialized, false)
+func (c *Client) list(ctx context.Context, ksql string) (*Response, error) {
+
+	payload := Payload{
+		Ksql: ksql,
+	}
+
+	return c.doRequest(ctx, &payload)
 }
 
-func (c *Client) updateStream(ctx context.Context, data StreamResourceModel, materialized bool) (*Source, error) {
-	// updating a stream is the same as creating it but with using "CREATE OR REPLACE" in the statement.
-	// Therefore, it can't be a source stream.
-	// Also, it must exist.
-	return c.doCreateStream(ctx, data, false, materialized, true)
+// serverInfo returns the response of the /info endpoint.
+func (c *Client) serverInfo(ctx context.Context) (*ServerInfo, error) {
+
+	var response struct {
+		Info ServerInfo `json:"KsqlServerInfo"`
+	}
+
+	if err := c.doGet(ctx, "/info", &response); err != nil {
+		return nil, err
+	}
+
+	return &response.Info, nil
 }
 
-func (c *Client) doCreateStream(ctx context.Context, data StreamResourceModel, source bool, materialized bool, mustExist bool) (*Source, error) {
+// healthCheck returns the response of the /healthcheck endpoint. ksqlDB responds with status 503 if the
+// server is unhealthy, which is not an error here.
+func (c *Client) healthCheck(ctx context.Context) (*HealthCheck, error) {
+
+	status, body, err := c.get(ctx, "/healthcheck")
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK && status != http.StatusServiceUnavailable {
+		return nil, responseError("/healthcheck", status, body)
+	}
+
+	var health HealthCheck
+	if err := json.Unmarshal(body, &health); err != nil {
+		return nil, err
+	}
+
+	return &health, nil
+}
+
+// clusterStatus returns the status of all hosts of the ksqlDB cluster as seen by the server, keyed by host.
+func (c *Client) clusterStatus(ctx context.Context) (map[string]HostStatus, error) {
+
+	var response struct {
+		ClusterStatus map[string]HostStatus `json:"clusterStatus"`
+	}
+
+	if err := c.doGet(ctx, "/clusterStatus", &response); err != nil {
+		return nil, err
+	}
+
+	return response.ClusterStatus, nil
+}
+
+// createStream creates the stream. Streams with a query are materialized by it, source streams are created
+// with CREATE SOURCE STREAM.
+func (c *Client) createStream(ctx context.Context, data StreamResourceModel) (*Source, error) {
+	return c.doCreateStream(ctx, data, false)
+}
+
+func (c *Client) updateStream(ctx context.Context, data StreamResourceModel) (*Source, error) {
+	// updating a stream is the same as creating it, but it must exist
+	return c.doCreateStream(ctx, data, true)
+}
+
+func (c *Client) doCreateStream(ctx context.Context, data StreamResourceModel, mustExist bool) (*Source, error) {
 
 	name := data.Name.ValueString()
 
 	if mustExist {
-		err := c.validateDoesExist(ctx, name)
-		if err != nil {
-			return nil, err
+		// in a dry run, the stream may only exist in the migrations
+		if !c.dryRun() {
+			err := c.validateDoesExist(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else if data.AdoptExisting.ValueBool() {
+		if existing, err := c.describe(ctx, name); err == nil {
+			return c.adoptStream(ctx, data, existing)
 		}
 	} else {
 		err := c.validateDoesNotExist(ctx, name)
@@ -166,42 +477,106 @@ func (c *Client) doCreateStream(ctx context.Context, data StreamResourceModel, s
 		}
 	}
 
-	ksql := createStreamKsql(ctx, name, source, materialized, data)
-
-	tflog.Info(ctx, fmt.Sprintf("Properties Raw: %v", data.Properties))
-
-	properties := make(map[string]string, len(data.Properties.Elements()))
-	data.Properties.ElementsAs(ctx, &properties, false)
-
-	tflog.Info(ctx, fmt.Sprintf("Properties made: %v", properties))
-
-	payload := Payload{
-		Ksql:       *ksql,
-		Properties: properties,
+	payload, err := c.streamPayload(ctx, data, mustExist)
+	if err != nil {
+		return nil, err
 	}
 
-	_, err := c.doRequest(ctx, &payload)
+	_, err = c.doRequest(ctx, payload)
 	if err != nil {
 		return nil, err
 	}
 
-	created, err := c.describe(ctx, name)
+	if c.dryRun() {
+		return &Source{Name: util.NormalizeIdentifier(name), Type: "STREAM", Statement: payload.Ksql}, nil
+	}
+
+	created, err := c.describeCreated(ctx, name)
 	if err != nil {
-		return nil, fmt.Errorf("this shouldn't happen") // TODO
+		return nil, &ReadBackError{Name: name, Err: err}
 	}
 
 	return created, nil
 }
 
+// describeCreated describes a stream or table which has just been created. It is retried since the command
+// may not have been applied by the node yet, even though statements wait for the commands sent before.
+func (c *Client) describeCreated(ctx context.Context, name string) (*Source, error) {
+
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		var created *Source
+		created, err = c.describe(ctx, name)
+		if err == nil || attempt == describeAttempts {
+			return created, err
+		}
+
+		tflog.Warn(ctx, fmt.Sprintf("Could not describe %s after creating it, retrying: %s", name, err))
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(describeRetryInterval):
+		}
+	}
+}
+
+// isNotFound returns whether the error is ksqlDB's reply to a DESCRIBE of a stream or table which doesn't exist.
+func isNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "Could not find STREAM/TABLE")
+}
+
+// dryRun returns whether statements are only written to the migrations instead of being executed.
+func (c *Client) dryRun() bool {
+	return c.migrations != nil && !c.executeMigrations
+}
+
+// streamPayload returns the request which createStream or, for an update, updateStream sends for the stream.
+func (c *Client) streamPayload(ctx context.Context, data StreamResourceModel, update bool) (*Payload, error) {
+
+	// updating a stream uses "CREATE OR REPLACE" in the statement, therefore it can't be a source stream
+	if update {
+		return c.createStreamPayload(ctx, data, false, !data.Query.IsNull(), true)
+	}
+
+	return c.createStreamPayload(ctx, data, data.Source.ValueBool(), !data.Query.IsNull(), false)
+}
+
+// createStreamPayload returns the request which creates or replaces the given stream.
+func (c *Client) createStreamPayload(ctx context.Context, data StreamResourceModel, source bool, materialized bool, update bool) (*Payload, error) {
+
+	// attributes the resource doesn't set fall back to the provider defaults, but only when the topic may be created:
+	// an existing topic keeps its settings, e.g. the default replicas would fail against a topic with another replication
+	if !update {
+		data = c.defaults.apply(data)
+	}
+
+	ksql := createStreamKsql(ctx, data.Name.ValueString(), source, materialized, data)
+
+	properties, diags := streamsProperties(ctx, data)
+	if diags.HasError() {
+		return nil, fmt.Errorf("invalid streams properties: %s", diags[0].Detail())
+	}
+	properties = c.defaults.streamsProperties(properties)
+
+	return &Payload{
+		Ksql:       *ksql,
+		Properties: properties,
+	}, nil
+}
+
 func (c *Client) dropStream(ctx context.Context, name string) error {
 
-	err := c.validateDoesExist(ctx, name)
-	if err != nil {
-		return err
+	if !c.dryRun() {
+		err := c.validateDoesExist(ctx, name)
+		if err != nil {
+			return err
+		}
 	}
 
 	payload := Payload{
-		Ksql: fmt.Sprintf("DROP STREAM %s;", name),
+		Ksql: dropStreamKsql(name),
 	}
 
 	response, err := c.doRequest(ctx, &payload)
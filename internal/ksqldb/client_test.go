@@ -0,0 +1,637 @@
+package ksqldb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"terraform-provider-ksqldb/internal/ksqldb/ksqldbtest"
+	"testing"
+	"time"
+)
+
+func testClient(t *testing.T) *Client {
+	server := testAccFakeServer(t)
+	url, username, password := server.URL, "", ""
+	return NewClient(&url, &username, &password)
+}
+
+// Streams with a query and source streams used to be created as plain streams, dropping the query and SOURCE.
+func TestClientCreateStreamKinds(t *testing.T) {
+	ctx := context.Background()
+	server := testAccFakeServer(t)
+	client := NewClusterClient([]string{server.URL}, "", "")
+
+	source := emptyStream("ORDERS")
+	source.KafkaTopic = types.StringValue("orders")
+	source.KeyFormat = types.StringValue("AVRO")
+	source.ValueFormat = types.StringValue("AVRO")
+	source.Source = types.BoolValue(true)
+
+	materialized := emptyStream("LARGE_ORDERS")
+	materialized.KafkaTopic = types.StringValue("large_orders")
+	materialized.Query = types.StringValue("SELECT * FROM ORDERS")
+
+	for _, data := range []StreamResourceModel{source, materialized} {
+		if _, err := client.createStream(ctx, data); err != nil {
+			t.Fatalf("unexpected error creating stream %s: %s", data.Name.ValueString(), err)
+		}
+	}
+
+	var statements []string
+	for _, request := range server.Requests() {
+		if strings.HasPrefix(request.Ksql, "CREATE") {
+			statements = append(statements, request.Ksql)
+		}
+	}
+
+	expected := []string{
+		"CREATE SOURCE STREAM ORDERS WITH (KAFKA_TOPIC = 'orders', KEY_FORMAT = 'AVRO', VALUE_FORMAT = 'AVRO');",
+		"CREATE OR REPLACE STREAM LARGE_ORDERS WITH (KAFKA_TOPIC = 'large_orders') AS SELECT * FROM ORDERS;",
+	}
+	if strings.Join(statements, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected statements:\n%s", strings.Join(statements, "\n"))
+	}
+}
+
+// The statement planned for an update used to be built like the one of a create, e.g. with SOURCE for source streams.
+func TestClientStreamPayload(t *testing.T) {
+	ctx := context.Background()
+	client := testClient(t)
+	replicas := int64(3)
+	client.defaults = StreamDefaults{Replicas: &replicas}
+
+	data := emptyStream("ORDERS")
+	data.KafkaTopic = types.StringValue("orders")
+	data.ValueFormat = types.StringValue("AVRO")
+	data.Source = types.BoolValue(true)
+
+	tests := []struct {
+		update bool
+		want   string
+	}{
+		{false, "CREATE SOURCE STREAM ORDERS WITH (KAFKA_TOPIC = 'orders', REPLICAS = '3', VALUE_FORMAT = 'AVRO');"},
+		{true, "CREATE OR REPLACE STREAM ORDERS WITH (KAFKA_TOPIC = 'orders', VALUE_FORMAT = 'AVRO');"},
+	}
+
+	for _, test := range tests {
+		payload, err := client.streamPayload(ctx, data, test.update)
+		if err != nil {
+			t.Fatalf("update %t: unexpected error: %s", test.update, err)
+		}
+		if payload.Ksql != test.want {
+			t.Errorf("update %t: expected %q, got %q", test.update, test.want, payload.Ksql)
+		}
+	}
+}
+
+func TestClientStreamLifecycle(t *testing.T) {
+	ctx := context.Background()
+	client := testClient(t)
+
+	data := StreamResourceModel{
+		Name:        types.StringValue("ORDERS"),
+		KafkaTopic:  types.StringValue("orders"),
+		KeyFormat:   types.StringValue("AVRO"),
+		ValueFormat: types.StringValue("AVRO"),
+		Properties:  types.MapNull(types.StringType),
+	}
+
+	created, err := client.createStream(ctx, data)
+	if err != nil {
+		t.Fatalf("unexpected error creating stream: %s", err)
+	}
+	if created.Name != "ORDERS" || created.Topic != "orders" || created.Type != "STREAM" {
+		t.Errorf("unexpected stream description: %+v", created)
+	}
+
+	_, err = client.createStream(ctx, data)
+	if err == nil || !strings.Contains(err.Error(), "already a stream or a table named ORDERS") {
+		t.Errorf("expected error creating an existing stream, got: %v", err)
+	}
+
+	if err := client.dropStream(ctx, "ORDERS"); err != nil {
+		t.Fatalf("unexpected error dropping stream: %s", err)
+	}
+
+	if _, err := client.describe(ctx, "ORDERS"); err == nil || !strings.Contains(err.Error(), "Could not find") {
+		t.Errorf("expected error describing a dropped stream, got: %v", err)
+	}
+}
+
+func TestClientDropMissingStream(t *testing.T) {
+	client := testClient(t)
+
+	err := client.dropStream(context.Background(), "MISSING")
+	if err == nil || err.Error() != "there is no stream or table named MISSING" {
+		t.Errorf("expected error dropping a missing stream, got: %v", err)
+	}
+}
+
+// testCassetteClient returns a client which replays the given cassette from testdata/cassettes. The cassettes
+// are written by hand after the responses of ksqlDB 0.29, they are not recordings of a server. Run the tests
+// with KSQLDB_RECORD=1 and the KSQLDB_* connection variables set to replace them with recordings.
+func testCassetteClient(t *testing.T, cassette string) *Client {
+	recorder := ksqldbtest.NewRecorder(t, filepath.Join("testdata", "cassettes", cassette+".json"))
+	url, username, password := recorder.Connection()
+
+	client := NewClient(&url, &username, &password)
+	client.client.Transport = recorder
+	return client
+}
+
+func TestClientCassetteDescribe(t *testing.T) {
+	for _, cassette := range []string{"describe_array_response", "describe_object_response"} {
+		t.Run(cassette, func(t *testing.T) {
+			source, err := testCassetteClient(t, cassette).describe(context.Background(), "ORDERS")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if source.Name != "ORDERS" || source.Topic != "orders" || source.KeyFormat != "AVRO" {
+				t.Errorf("unexpected stream description: %+v", source)
+			}
+		})
+	}
+}
+
+func TestClientCassetteErrors(t *testing.T) {
+	tests := map[string]string{
+		"describe_not_found":  "Could not find STREAM/TABLE 'ORDERS' in the Metastore",
+		"unexpected_response": "response must be object or list",
+	}
+
+	for cassette, expected := range tests {
+		t.Run(cassette, func(t *testing.T) {
+			_, err := testCassetteClient(t, cassette).describe(context.Background(), "ORDERS")
+			if err == nil || err.Error() != expected {
+				t.Errorf("expected error %q, got: %v", expected, err)
+			}
+		})
+	}
+}
+
+func TestClientCassetteEmptyResponse(t *testing.T) {
+	client := testCassetteClient(t, "empty_array_response")
+
+	_, err := client.describe(context.Background(), "ORDERS")
+	if err == nil || err.Error() != "ksqlDB returned no description of ORDERS" {
+		t.Errorf("expected an error for an empty description, got: %v", err)
+	}
+}
+
+func TestClientCassetteCreateStream(t *testing.T) {
+	ctx := context.Background()
+	client := testCassetteClient(t, "create_stream_redacted")
+
+	properties, _ := types.MapValue(types.StringType, map[string]attr.Value{
+		"auto.offset.reset": types.StringValue("earliest"),
+		// the credentials are redacted in the cassette
+		"ksql.schema.registry.basic.auth.user.info": types.StringValue("user:secret"),
+	})
+
+	data := StreamResourceModel{
+		Name:          types.StringValue("ORDERS"),
+		KafkaTopic:    types.StringValue("orders"),
+		KeyFormat:     types.StringValue("AVRO"),
+		ValueFormat:   types.StringValue("AVRO"),
+		KeySchemaId:   types.Int64Value(6),
+		ValueSchemaId: types.Int64Value(7),
+		Properties:    properties,
+	}
+
+	created, err := client.createStream(ctx, data)
+	if err != nil {
+		t.Fatalf("unexpected error creating stream: %s", err)
+	}
+	if created.Statement == "" || created.Type != "STREAM" {
+		t.Errorf("unexpected stream description: %+v", created)
+	}
+}
+
+// A failed request used to keep the lock which serializes the requests, so every following request hung.
+func TestClientReleasesLockAfterError(t *testing.T) {
+	ctx := context.Background()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	if _, err := NewClusterClient([]string{closed.URL}, "", "").describe(ctx, "ORDERS"); err == nil {
+		t.Fatal("expected an error from a stopped server")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := testClient(t).describe(ctx, "ORDERS")
+		done <- err
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the request after a failed one didn't complete")
+	}
+}
+
+func TestClientFailover(t *testing.T) {
+	ctx := context.Background()
+
+	// a node which refuses connections
+	down := ksqldbtest.NewServer()
+	down.Close()
+
+	unhealthy := testAccFakeServer(t)
+	unhealthy.SetHealthy("kafka", false)
+
+	healthy := testAccFakeServer(t)
+
+	client := NewClusterClient([]string{down.URL, unhealthy.URL, healthy.URL}, "", "")
+
+	data := emptyStream("ORDERS")
+	data.KafkaTopic = types.StringValue("orders")
+	data.KeyFormat = types.StringValue("AVRO")
+	data.ValueFormat = types.StringValue("AVRO")
+
+	if _, err := client.createStream(ctx, data); err != nil {
+		t.Fatalf("unexpected error creating stream: %s", err)
+	}
+
+	if len(unhealthy.Requests()) != 0 {
+		t.Errorf("expected no requests to the unhealthy node, got %d", len(unhealthy.Requests()))
+	}
+	if len(healthy.Requests()) == 0 {
+		t.Error("expected requests to the healthy node")
+	}
+}
+
+func TestClientNoFailoverAfterSend(t *testing.T) {
+	ctx := context.Background()
+
+	// a node which accepts the request but closes the connection without responding
+	hijacked := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthcheck" {
+			_, _ = w.Write([]byte(`{"isHealthy":true}`))
+			return
+		}
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		_ = conn.Close()
+	}))
+	t.Cleanup(hijacked.Close)
+
+	other := testAccFakeServer(t)
+
+	client := NewClusterClient([]string{hijacked.URL, other.URL}, "", "")
+
+	// read-only statements are sent to the next node
+	if _, err := client.list(ctx, "SHOW STREAMS;"); err != nil {
+		t.Fatalf("unexpected error listing streams: %s", err)
+	}
+
+	client.prefer(hijacked.URL)
+	requests := len(other.Requests())
+
+	_, err := client.list(ctx, "DROP STREAM ORDERS;")
+	if err == nil || !strings.Contains(err.Error(), "may have been executed") {
+		t.Errorf("expected error without failover, got: %v", err)
+	}
+	if len(other.Requests()) != requests {
+		t.Error("expected the statement not to be sent to another node")
+	}
+}
+
+func TestClientWaitsForCommands(t *testing.T) {
+	ctx := context.Background()
+
+	server := testAccFakeServer(t)
+	server.SetQueueCommands(true)
+
+	client := NewClusterClient([]string{server.URL}, "", "")
+
+	data := emptyStream("ORDERS")
+	data.KafkaTopic = types.StringValue("orders")
+	data.KeyFormat = types.StringValue("AVRO")
+	data.ValueFormat = types.StringValue("AVRO")
+
+	// the queued CREATE is polled via /status before the stream is described
+	if _, err := client.createStream(ctx, data); err != nil {
+		t.Fatalf("unexpected error creating stream: %s", err)
+	}
+
+	requests := server.Requests()
+	last := requests[len(requests)-1]
+	if last.Ksql != "DESCRIBE ORDERS;" || last.CommandSequenceNumber == nil || *last.CommandSequenceNumber != 1 {
+		t.Errorf("expected DESCRIBE to wait for command 1, got: %+v", last)
+	}
+}
+
+func TestClientCreateReadBackError(t *testing.T) {
+	ctx := context.Background()
+
+	interval := describeRetryInterval
+	describeRetryInterval = time.Millisecond
+	t.Cleanup(func() { describeRetryInterval = interval })
+
+	server := testAccFakeServer(t)
+
+	// the server creates streams but never finds them afterwards
+	var describes int
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "DESCRIBE") {
+			describes++
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"@type":"statement_error","error_code":40001,"message":"Could not find STREAM/TABLE 'ORDERS' in the Metastore"}`))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(proxy.Close)
+
+	data := emptyStream("ORDERS")
+	data.KafkaTopic = types.StringValue("orders")
+	data.KeyFormat = types.StringValue("AVRO")
+	data.ValueFormat = types.StringValue("AVRO")
+
+	_, err := NewClusterClient([]string{proxy.URL}, "", "").createStream(ctx, data)
+
+	var readBack *ReadBackError
+	if !errors.As(err, &readBack) || !strings.Contains(err.Error(), "Could not find") {
+		t.Fatalf("expected a read back error with the underlying cause, got: %v", err)
+	}
+	// one DESCRIBE to check that the stream doesn't exist yet
+	if describes != 1+describeAttempts {
+		t.Errorf("expected %d attempts to describe the stream, got %d", describeAttempts, describes-1)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := testClient(t).describe(ctx, "MISSING")
+	if !isNotFound(err) {
+		t.Errorf("expected a missing stream not to be found, got: %v", err)
+	}
+
+	unauthorized := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"@type":"generic_error","error_code":40100,"message":"Unauthorized"}`))
+	}))
+	t.Cleanup(unauthorized.Close)
+
+	_, err = NewClusterClient([]string{unauthorized.URL}, "", "").describe(ctx, "ORDERS")
+	if err == nil || isNotFound(err) {
+		t.Errorf("expected an unauthorized request to fail with another error, got: %v", err)
+	}
+}
+
+func TestClientReadOnly(t *testing.T) {
+	server := testAccFakeServer(t)
+	if err := server.Exec("CREATE STREAM ORDERS (ID STRING) WITH (KAFKA_TOPIC='orders', VALUE_FORMAT='JSON', PARTITIONS=1);"); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClusterClient([]string{server.URL}, "", "")
+	client.readOnly = true
+
+	err := client.dropStream(context.Background(), "ORDERS")
+	if err == nil || !strings.Contains(err.Error(), "read-only, refusing to execute: DROP STREAM ORDERS;") {
+		t.Errorf("expected the drop to be refused, got: %v", err)
+	}
+
+	for _, request := range server.Requests() {
+		if !isReadOnlyStatement(request.Ksql) {
+			t.Errorf("unexpected statement sent by a read-only client: %s", request.Ksql)
+		}
+	}
+}
+
+// Only the first statement used to be checked, so DESCRIBE X; DROP STREAM Y; counted as read-only.
+func TestIsReadOnlyStatement(t *testing.T) {
+
+	tests := []struct {
+		ksql string
+		want bool
+	}{
+		{"DESCRIBE ORDERS;", true},
+		{"  list streams", true},
+		{"-- comment\nSHOW QUERIES;", true},
+		{"DESCRIBE ORDERS; EXPLAIN Q1;", true},
+		{"DESCRIBE ORDERS; DROP STREAM PAYMENTS;", false},
+		{"DROP STREAM PAYMENTS; DESCRIBE ORDERS;", false},
+		{"CREATE STREAM DESCRIBE_ME WITH (KAFKA_TOPIC='describe');", false},
+		{"DESCRIBE 'unterminated", false},
+		{"", false},
+		{";", false},
+	}
+
+	for _, test := range tests {
+		if got := isReadOnlyStatement(test.ksql); got != test.want {
+			t.Errorf("%q: expected %t, got %t", test.ksql, test.want, got)
+		}
+	}
+}
+
+// Executed migrations used to be written before the statement was sent, so failed statements were kept.
+func TestClientExecutedMigrations(t *testing.T) {
+	ctx := context.Background()
+	server := testAccFakeServer(t)
+	if err := server.Exec("CREATE STREAM ORDERS (ID STRING) WITH (KAFKA_TOPIC='orders', VALUE_FORMAT='JSON', PARTITIONS=1);"); err != nil {
+		t.Fatal(err)
+	}
+
+	directory := t.TempDir()
+	client := NewClusterClient([]string{server.URL}, "", "")
+	client.migrations = NewMigrationWriter(directory)
+	client.executeMigrations = true
+
+	_, err := client.doRequest(ctx, &Payload{Ksql: "CREATE STREAM ORDERS (ID STRING) WITH (KAFKA_TOPIC='orders', VALUE_FORMAT='JSON', PARTITIONS=1);"})
+	if err == nil {
+		t.Fatal("expected an error creating an existing stream")
+	}
+	if entries, _ := os.ReadDir(directory); len(entries) != 0 {
+		t.Errorf("expected no migration of a failed statement, got %d", len(entries))
+	}
+
+	if _, err := client.doRequest(ctx, &Payload{Ksql: "DROP STREAM ORDERS;"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(directory, "V000001__drop_stream_orders.sql")); err != nil {
+		t.Errorf("expected a migration of the executed statement: %s", err)
+	}
+}
+
+func TestClientLogRedaction(t *testing.T) {
+	server := testAccFakeServer(t)
+	if err := server.Exec("CREATE STREAM ORDERS (ID STRING) WITH (KAFKA_TOPIC='orders', VALUE_FORMAT='JSON', PARTITIONS=1);"); err != nil {
+		t.Fatal(err)
+	}
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	client := NewClusterClient([]string{server.URL}, "", "")
+	limit := 32
+	client.logBodyLimit = &limit
+
+	_, err := client.doRequest(ctx, &Payload{
+		Ksql: "SHOW STREAMS;",
+		Properties: map[string]string{
+			"auto.offset.reset":                         "earliest",
+			"sasl.jaas.config":                          `org.apache.kafka.common.security.plain.PlainLoginModule required username="user" password="secret";`,
+			"ksql.schema.registry.basic.auth.user.info": "user:secret",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(output.String(), "secret") {
+		t.Errorf("expected credentials to be masked:\n%s", output.String())
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var statement, response map[string]interface{}
+	for _, entry := range entries {
+		switch entry["@message"] {
+		case "Executing KSQL statement":
+			statement = entry
+		case "KSQL response body":
+			response = entry
+		}
+	}
+
+	if statement["streams_properties.auto.offset.reset"] != "earliest" || statement["streams_properties.sasl.jaas.config"] != "***" {
+		t.Errorf("unexpected statement log entry: %v", statement)
+	}
+	if body, _ := response["body"].(string); !strings.HasSuffix(body, "more bytes)") || len(body) > limit+32 {
+		t.Errorf("expected the response body to be truncated, got: %v", response)
+	}
+}
+
+func TestClientHTTPSettings(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	// a proxy which forwards to the fake server, unless the request takes too long
+	var requests []*http.Request
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "LIST TOPICS") {
+			time.Sleep(500 * time.Millisecond)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(proxy.Close)
+
+	proxyUrl, _ := url.Parse(proxy.URL)
+
+	client := NewClusterClient([]string{"http://ksqldb.invalid:8088"}, "user", "password")
+	client.configureHTTP(HTTPSettings{
+		Timeout:   100 * time.Millisecond,
+		ProxyURL:  proxyUrl,
+		Headers:   map[string]string{"X-Team": "payments"},
+		UserAgent: "pipeline/1",
+	}, "1.2.3")
+
+	if _, err := client.list(context.Background(), "SHOW STREAMS;"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 1 {
+		t.Fatalf("expected the request to be sent through the proxy, got %d requests", len(requests))
+	}
+	request := requests[0]
+	if request.Host != "ksqldb.invalid:8088" || request.Header.Get("X-Team") != "payments" ||
+		request.Header.Get("User-Agent") != "pipeline/1 terraform-provider-ksqldb/1.2.3" {
+		t.Errorf("unexpected request: host %s, headers %v", request.Host, request.Header)
+	}
+	if username, password, ok := request.BasicAuth(); !ok || username != "user" || password != "password" {
+		t.Errorf("expected basic authentication, got %v", request.Header)
+	}
+
+	_, err := client.list(context.Background(), "LIST TOPICS;")
+	if err == nil || !strings.Contains(err.Error(), "Client.Timeout exceeded") {
+		t.Errorf("expected the request to time out, got: %v", err)
+	}
+}
+
+func TestClientServerInfo(t *testing.T) {
+	server := testAccFakeServer(t)
+	server.SetVersion("0.28.2")
+	client := NewClusterClient([]string{server.URL}, "", "")
+
+	info, err := client.serverInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ServerInfo{Version: "0.28.2", KafkaClusterId: "fake-kafka-cluster", KsqlServiceId: "default_", ServerStatus: "RUNNING"}
+	if *info != expected {
+		t.Errorf("expected %+v, got %+v", expected, *info)
+	}
+}
+
+func TestClientHealthCheck(t *testing.T) {
+	ctx := context.Background()
+	server := testAccFakeServer(t)
+	client := NewClusterClient([]string{server.URL}, "", "")
+
+	health, err := client.healthCheck(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !health.IsHealthy || !health.Details["kafka"].IsHealthy {
+		t.Errorf("expected a healthy server, got %+v", *health)
+	}
+
+	// ksqlDB responds with 503, which is reported as unhealthy rather than as an error
+	server.SetHealthy("kafka", false)
+
+	health, err = client.healthCheck(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if health.IsHealthy || health.Details["kafka"].IsHealthy || !health.Details["metastore"].IsHealthy {
+		t.Errorf("expected an unhealthy Kafka, got %+v", *health)
+	}
+
+	unauthorized := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"@type":"generic_error","error_code":40100,"message":"Unauthorized"}`))
+	}))
+	t.Cleanup(unauthorized.Close)
+
+	_, err = NewClusterClient([]string{unauthorized.URL}, "", "").healthCheck(ctx)
+	if err == nil || err.Error() != "Unauthorized" {
+		t.Errorf("expected an error for other statuses, got: %v", err)
+	}
+}
+
+func TestClientClusterStatus(t *testing.T) {
+	server := testAccFakeServer(t)
+	client := NewClusterClient([]string{server.URL}, "", "")
+
+	hosts, err := client.clusterStatus(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	if len(hosts) != 1 || !hosts[host].HostAlive || hosts[host].LastStatusUpdateMs != 1700000000000 {
+		t.Errorf("expected %s to be alive, got %+v", host, hosts)
+	}
+}
@@ -0,0 +1,79 @@
+package ksqldb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	commandPollInterval = 500 * time.Millisecond
+	commandTimeout      = 60 * time.Second
+)
+
+func (c *Client) lastCommandSequenceNumber() *int64 {
+	c.commandMutex.Lock()
+	defer c.commandMutex.Unlock()
+
+	if c.commandSequenceNumber == nil {
+		return nil
+	}
+	sequenceNumber := *c.commandSequenceNumber
+	return &sequenceNumber
+}
+
+// commandExecuted records the sequence number of a command so that subsequent statements wait for it.
+func (c *Client) commandExecuted(sequenceNumber int64) {
+	c.commandMutex.Lock()
+	defer c.commandMutex.Unlock()
+
+	if c.commandSequenceNumber == nil || sequenceNumber > *c.commandSequenceNumber {
+		c.commandSequenceNumber = &sequenceNumber
+	}
+}
+
+// waitForCommand polls /status/<commandId> until the command has been executed. This is only necessary if
+// the server responded before executing the command, e.g. because it timed out waiting for it.
+func (c *Client) waitForCommand(ctx context.Context, commandId string, status CommandStatus) error {
+
+	deadline := time.Now().Add(commandTimeout)
+
+	for {
+		switch status.Status {
+		case "SUCCESS", "TERMINATED":
+			return nil
+		case "ERROR":
+			return errors.New(status.Message)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for command %s, its last status was %s", commandId, status.Status)
+		}
+
+		tflog.Info(ctx, fmt.Sprintf("Waiting for command %s with status %s", commandId, status.Status))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(commandPollInterval):
+		}
+
+		if err := c.doGet(ctx, commandStatusEndpoint(commandId), &status); err != nil {
+			return err
+		}
+	}
+}
+
+// commandStatusEndpoint returns the endpoint of a command's status. Command IDs consist of the type, the entity
+// and the action separated by slashes, e.g. stream/`ORDERS`/create.
+func commandStatusEndpoint(commandId string) string {
+	segments := strings.Split(commandId, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return "/status/" + strings.Join(segments, "/")
+}
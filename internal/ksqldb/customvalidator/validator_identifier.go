@@ -14,11 +14,14 @@ var _ validator.String = identifierValidator{}
 
 // identifierValidator validates that an identifier is valid.
 type identifierValidator struct {
+	// caseInsensitive allows lowercase letters in identifiers which are not enclosed by backticks.
+	// ksqlDB converts those identifiers to uppercase.
+	caseInsensitive bool
 }
 
 // Description describes the validation in plain text formatting.
 func (v identifierValidator) Description(_ context.Context) string {
-	return "identifier must only contain valid characters"
+	return "identifier must only contain valid characters and must not be a reserved word"
 }
 
 // MarkdownDescription describes the validation in Markdown formatting.
@@ -35,6 +38,15 @@ func (v identifierValidator) ValidateString(ctx context.Context, request validat
 
 	value := request.ConfigValue.ValueString()
 
+	if len(util.NormalizeIdentifier(value)) == 0 {
+		response.Diagnostics.Append(diag.NewAttributeErrorDiagnostic(
+			request.Path,
+			v.Description(ctx),
+			"The identifier must not be empty.",
+		))
+		return
+	}
+
 	if strings.Contains(value, ";") {
 		response.Diagnostics.Append(diag.NewAttributeErrorDiagnostic(
 			request.Path,
@@ -49,12 +61,28 @@ func (v identifierValidator) ValidateString(ctx context.Context, request validat
 	}
 
 	// others only allow capital letters, numbers and underscore
-	matched, _ := regexp.MatchString(`^[A-Z0-9_]+$`, value)
+	pattern := `^[A-Z0-9_]+$`
+	message := "The identifier '%s' must only contain uppercase letters, numbers or underscore if it is not enclosed by backticks."
+	if v.caseInsensitive {
+		pattern = `^[a-zA-Z0-9_]+$`
+		message = "The identifier '%s' must only contain letters, numbers or underscore if it is not enclosed by backticks."
+	}
+
+	matched, _ := regexp.MatchString(pattern, value)
 	if !matched {
 		response.Diagnostics.Append(diag.NewAttributeErrorDiagnostic(
 			request.Path,
 			v.Description(ctx),
-			fmt.Sprintf("The identifier '%s' must only contain uppercase letters, numbers or underscore if it is not enclosed by backticks.", value),
+			fmt.Sprintf(message, value),
+		))
+		return
+	}
+
+	if util.IsReservedWord(value) {
+		response.Diagnostics.Append(diag.NewAttributeErrorDiagnostic(
+			request.Path,
+			v.Description(ctx),
+			fmt.Sprintf("The identifier '%s' is a reserved word in ksqlDB. Enclose it in backticks (`%s`) to use it as an identifier.", value, value),
 		))
 	}
 }
@@ -62,8 +90,17 @@ func (v identifierValidator) ValidateString(ctx context.Context, request validat
 // Identifier returns an AttributeValidator which ensures that any configured
 // attribute value:
 //
+//   - Is not empty.
 //   - Does not contain a semicolon.
-//   - Only contains letters, numbers or underscore when the identifier is not enclosed by backticks.
+//   - Only contains uppercase letters, numbers or underscore when the identifier is not enclosed by backticks.
+//   - Is not a reserved word when the identifier is not enclosed by backticks.
 func Identifier() validator.String {
 	return identifierValidator{}
 }
+
+// CaseInsensitiveIdentifier returns an AttributeValidator which works like Identifier but also
+// accepts lowercase letters in identifiers which are not enclosed by backticks. ksqlDB converts
+// such identifiers to uppercase, so the attribute must be compared using util.NormalizeIdentifier.
+func CaseInsensitiveIdentifier() validator.String {
+	return identifierValidator{caseInsensitive: true}
+}
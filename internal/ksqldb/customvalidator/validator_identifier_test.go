@@ -0,0 +1,69 @@
+package customvalidator
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
+	"testing"
+)
+
+// validateString validates the value of an attribute which doesn't depend on other attributes.
+func validateString(v validator.String, value types.String) diag.Diagnostics {
+	request := validator.StringRequest{Path: path.Root("name"), ConfigValue: value}
+	response := validator.StringResponse{}
+	v.ValidateString(context.Background(), request, &response)
+	return response.Diagnostics
+}
+
+func TestIdentifier(t *testing.T) {
+
+	tests := []struct {
+		name            string
+		value           string
+		caseInsensitive bool
+		// error is a part of the expected error, empty if the value is valid
+		error string
+	}{
+		{name: "uppercase", value: "ORDERS_V2"},
+		{name: "lowercase", value: "orders", error: "must only contain uppercase letters"},
+		{name: "lowercase, case insensitive", value: "orders", caseInsensitive: true},
+		{name: "mixed case, case insensitive", value: "Orders_v2", caseInsensitive: true},
+		{name: "back ticked", value: "`my orders`"},
+		{name: "reserved word", value: "SELECT", error: "Enclose it in backticks (`SELECT`)"},
+		{name: "reserved word, case insensitive", value: "stream", caseInsensitive: true, error: "is a reserved word"},
+		{name: "back ticked reserved word", value: "`SELECT`"},
+		{name: "empty", value: "", error: "must not be empty"},
+		{name: "empty back ticks", value: "``", error: "must not be empty"},
+		{name: "single backtick", value: "`", error: "must only contain uppercase letters"},
+		{name: "semicolon", value: "`A;DROP`", error: "must not contain a semicolon"},
+		{name: "dash", value: "ORDERS-V2", caseInsensitive: true, error: "must only contain letters"},
+	}
+
+	for _, test := range tests {
+		v := Identifier()
+		if test.caseInsensitive {
+			v = CaseInsensitiveIdentifier()
+		}
+
+		diags := validateString(v, types.StringValue(test.value))
+
+		if test.error == "" {
+			if diags.HasError() {
+				t.Errorf("%s: expected %q to be valid, got %v", test.name, test.value, diags)
+			}
+			continue
+		}
+		if !diags.HasError() || !strings.Contains(diags.Errors()[0].Detail(), test.error) {
+			t.Errorf("%s: expected %q to fail with %q, got %v", test.name, test.value, test.error, diags)
+		}
+	}
+
+	for _, value := range []types.String{types.StringNull(), types.StringUnknown()} {
+		if diags := validateString(Identifier(), value); diags.HasError() {
+			t.Errorf("%s: expected no validation, got %v", value, diags)
+		}
+	}
+}
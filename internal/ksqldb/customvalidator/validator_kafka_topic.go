@@ -6,17 +6,21 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"regexp"
+	"strings"
 )
 
+// maxTopicNameLength is the maximum length of a topic name as defined by Kafka's Topic.validate.
+const maxTopicNameLength = 249
+
 var _ validator.String = kafkaTopicValidator{}
 
-// kafkaTopicValidator validates that an identifier is valid.
+// kafkaTopicValidator validates that a Kafka topic name is valid.
 type kafkaTopicValidator struct {
 }
 
 // Description describes the validation in plain text formatting.
 func (v kafkaTopicValidator) Description(_ context.Context) string {
-	return "topic name must be at max 255 characters and must only contain valid characters"
+	return fmt.Sprintf("topic name must be at max %d characters and must only contain valid characters", maxTopicNameLength)
 }
 
 // MarkdownDescription describes the validation in Markdown formatting.
@@ -33,15 +37,33 @@ func (v kafkaTopicValidator) ValidateString(ctx context.Context, request validat
 
 	value := request.ConfigValue.ValueString()
 
-	if len(value) > 255 {
+	if len(value) == 0 {
 		response.Diagnostics.Append(diag.NewAttributeErrorDiagnostic(
 			request.Path,
 			v.Description(ctx),
-			fmt.Sprintf("The topic name '%s' is too long. Must be up to 255 characters in length.", value),
+			"The topic name must not be empty.",
 		))
+		return
 	}
 
-	// others only allow letters, numbers and underscore
+	if value == "." || value == ".." {
+		response.Diagnostics.Append(diag.NewAttributeErrorDiagnostic(
+			request.Path,
+			v.Description(ctx),
+			fmt.Sprintf("The topic name '%s' is not allowed.", value),
+		))
+		return
+	}
+
+	if len(value) > maxTopicNameLength {
+		response.Diagnostics.Append(diag.NewAttributeErrorDiagnostic(
+			request.Path,
+			v.Description(ctx),
+			fmt.Sprintf("The topic name '%s' is too long. Must be up to %d characters in length.", value, maxTopicNameLength),
+		))
+	}
+
+	// only allow letters, numbers, dot, underscore and dash
 	matches, _ := regexp.MatchString("^[a-zA-Z0-9._-]+$", value)
 	if !matches {
 		response.Diagnostics.Append(diag.NewAttributeErrorDiagnostic(
@@ -49,14 +71,28 @@ func (v kafkaTopicValidator) ValidateString(ctx context.Context, request validat
 			v.Description(ctx),
 			fmt.Sprintf("The topic name '%s' is invalid. It can include the following characters: a-z, A-Z, 0-9, . (dot), _ (underscore), and - (dash).", value),
 		))
+		return
+	}
+
+	// Kafka replaces dots with underscores in metric names, so e.g. "a.b" and "a_b" would collide
+	if strings.Contains(value, ".") && strings.Contains(value, "_") {
+		response.Diagnostics.Append(diag.NewAttributeWarningDiagnostic(
+			request.Path,
+			"Topic name may collide in metrics",
+			fmt.Sprintf("The topic name '%s' contains both . (dot) and _ (underscore). Due to limitations in metric names, "+
+				"topics with a period or underscore could collide. To avoid issues it is best to use either, but not both.", value),
+		))
 	}
 }
 
 // KafkaTopic returns an AttributeValidator which ensures that any configured
-// attribute value:
+// attribute value follows the rules of Kafka's Topic.validate:
 //
-//   - Is at max 255 characters long.
+//   - Is not empty and neither "." nor "..".
+//   - Is at max 249 characters long.
 //   - Only contains the following characters: a-z, A-Z, 0-9, . (dot), _ (underscore), and - (dash).
+//
+// Additionally, a warning is issued if the name contains both a dot and an underscore.
 func KafkaTopic() validator.String {
 	return kafkaTopicValidator{}
 }
@@ -0,0 +1,51 @@
+package customvalidator
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
+	"testing"
+)
+
+func TestKafkaTopic(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		value string
+		// error is a part of the expected error, empty if the value is valid
+		error string
+		// warning is a part of the expected warning, empty if there is none
+		warning string
+	}{
+		{name: "letters, numbers and dashes", value: "Orders-v2"},
+		{name: "dots", value: "shop.orders.v2"},
+		{name: "underscores", value: "shop_orders_v2"},
+		{name: "dots and underscores", value: "shop.orders_v2", warning: "contains both . (dot) and _ (underscore)"},
+		{name: "maximum length", value: strings.Repeat("a", 249)},
+		{name: "too long", value: strings.Repeat("a", 250), error: "is too long. Must be up to 249 characters"},
+		{name: "empty", value: "", error: "must not be empty"},
+		{name: "dot", value: ".", error: "is not allowed"},
+		{name: "two dots", value: "..", error: "is not allowed"},
+		{name: "three dots", value: "..."},
+		{name: "space", value: "shop orders", error: "is invalid"},
+		{name: "semicolon", value: "orders;", error: "is invalid"},
+	}
+
+	for _, test := range tests {
+		diags := validateString(KafkaTopic(), types.StringValue(test.value))
+
+		if test.error == "" && diags.HasError() {
+			t.Errorf("%s: expected %q to be valid, got %v", test.name, test.value, diags)
+		}
+		if test.error != "" && (!diags.HasError() || !strings.Contains(diags.Errors()[0].Detail(), test.error)) {
+			t.Errorf("%s: expected %q to fail with %q, got %v", test.name, test.value, test.error, diags)
+		}
+
+		warnings := diags.Warnings()
+		if test.warning == "" && len(warnings) > 0 {
+			t.Errorf("%s: expected no warning for %q, got %v", test.name, test.value, warnings)
+		}
+		if test.warning != "" && (len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), test.warning)) {
+			t.Errorf("%s: expected %q to warn with %q, got %v", test.name, test.value, test.warning, warnings)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"strings"
+	"terraform-provider-ksqldb/internal/ksqldb/parser"
 )
 
 var _ validator.String = queryValidator{}
@@ -54,9 +55,8 @@ func (v queryValidator) ValidateString(ctx context.Context, request validator.St
 		))
 	}
 
-	// ensure it is a select statement
-	if !strings.HasPrefix(strings.ToUpper(value), "SELECT ") {
-		// TODO
+	// ensure it is a select statement, the clauses are compared on updates
+	if query, err := parser.ParseQuery(value); err != nil || query.From == "" {
 		response.Diagnostics.Append(diag.NewAttributeErrorDiagnostic(
 			request.Path,
 			v.Description(ctx),
@@ -69,7 +69,7 @@ func (v queryValidator) ValidateString(ctx context.Context, request validator.St
 // attribute value:
 //
 //   - The query does not contain a semicolon
-//   - The query starts with "SELECT "
+//   - The query is a SELECT query with a FROM clause, which the KSQL parser can split into its clauses
 func Query() validator.String {
 	return queryValidator{}
 }
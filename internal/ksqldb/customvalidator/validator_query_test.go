@@ -0,0 +1,63 @@
+package customvalidator
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"testing"
+)
+
+// queryConfig returns a configuration with the given query and source attribute.
+func queryConfig(query string, source bool) tfsdk.Config {
+
+	s := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"query":  schema.StringAttribute{Optional: true},
+			"source": schema.BoolAttribute{Optional: true},
+		},
+	}
+
+	raw := tftypes.NewValue(s.Type().TerraformType(context.Background()), map[string]tftypes.Value{
+		"query":  tftypes.NewValue(tftypes.String, query),
+		"source": tftypes.NewValue(tftypes.Bool, source),
+	})
+
+	return tfsdk.Config{Schema: s, Raw: raw}
+}
+
+func TestQuery(t *testing.T) {
+
+	tests := []struct {
+		query  string
+		source bool
+		valid  bool
+	}{
+		{"SELECT * FROM ORDERS EMIT CHANGES", false, true},
+		{"select id,\n  amount from orders", false, true},
+		{"-- large orders\nSELECT * FROM ORDERS WHERE AMOUNT > 100", false, true},
+		{"SELECT * FROM ORDERS", true, false},
+		{"SELECT * FROM ORDERS; DROP STREAM ORDERS", false, false},
+		{"INSERT INTO ORDERS SELECT * FROM PAYMENTS", false, false},
+		{"SELECT 'unterminated FROM ORDERS", false, false},
+		{"SELECT", false, false},
+	}
+
+	for _, test := range tests {
+		request := validator.StringRequest{
+			Path:        path.Root("query"),
+			Config:      queryConfig(test.query, test.source),
+			ConfigValue: types.StringValue(test.query),
+		}
+		response := validator.StringResponse{}
+
+		Query().ValidateString(context.Background(), request, &response)
+
+		if valid := !response.Diagnostics.HasError(); valid != test.valid {
+			t.Errorf("%q (source %t): expected valid %t, got %t: %v", test.query, test.source, test.valid, valid, response.Diagnostics)
+		}
+	}
+}
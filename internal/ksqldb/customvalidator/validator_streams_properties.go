@@ -0,0 +1,99 @@
+package customvalidator
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"slices"
+	"strings"
+)
+
+var _ validator.Map = streamsPropertiesValidator{}
+
+// knownStreamsPropertyPrefixes are prefixes of properties which are passed on by ksqlDB, e.g. to the
+// Kafka clients of a query.
+var knownStreamsPropertyPrefixes = []string{
+	"ksql.", "producer.", "consumer.", "main.consumer.", "restore.consumer.", "global.consumer.", "admin.",
+}
+
+// knownStreamsProperties are Kafka Streams properties without prefix which ksqlDB accepts.
+var knownStreamsProperties = []string{
+	"acceptable.recovery.lag",
+	"buffered.records.per.partition",
+	"default.deserialization.exception.handler",
+	"default.production.exception.handler",
+	"max.task.idle.ms",
+	"max.warmup.replicas",
+	"num.standby.replicas",
+	"poll.ms",
+	"probing.rebalance.interval.ms",
+	"replication.factor",
+	"state.cleanup.delay.ms",
+	"state.dir",
+	"statestore.cache.max.bytes",
+	"task.timeout.ms",
+	"topology.optimization",
+}
+
+// streamsPropertiesValidator warns about properties ksqlDB will most likely ignore.
+type streamsPropertiesValidator struct {
+	typed map[string]string
+}
+
+// Description describes the validation in plain text formatting.
+func (v streamsPropertiesValidator) Description(_ context.Context) string {
+	return "properties should be known ksqlDB or Kafka Streams properties"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v streamsPropertiesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateMap performs the validation.
+func (v streamsPropertiesValidator) ValidateMap(ctx context.Context, request validator.MapRequest, response *validator.MapResponse) {
+
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for key := range request.ConfigValue.Elements() {
+
+		if attribute, ok := v.typed[key]; ok {
+			response.Diagnostics.Append(diag.NewAttributeWarningDiagnostic(
+				request.Path.AtMapKey(key),
+				"Typed Streams Property Available",
+				fmt.Sprintf("Use the validated streams_properties.%s attribute instead of the property '%s'. If both are set, the attribute takes precedence.", attribute, key),
+			))
+			continue
+		}
+
+		if slices.Contains(knownStreamsProperties, key) || hasKnownPrefix(key) {
+			continue
+		}
+
+		response.Diagnostics.Append(diag.NewAttributeWarningDiagnostic(
+			request.Path.AtMapKey(key),
+			v.Description(ctx),
+			fmt.Sprintf("Unknown streams property '%s'. It is sent to ksqlDB as is, which ignores properties it doesn't know.", key),
+		))
+	}
+}
+
+func hasKnownPrefix(key string) bool {
+	for _, prefix := range knownStreamsPropertyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamsProperties returns an AttributeValidator which warns if any key of the configured map:
+//
+//   - Is available as a typed attribute, given as a map of property keys to attribute names
+//   - Is not a known ksqlDB or Kafka Streams property
+func StreamsProperties(typed map[string]string) validator.Map {
+	return streamsPropertiesValidator{typed: typed}
+}
@@ -0,0 +1,50 @@
+package customvalidator
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
+	"testing"
+)
+
+func TestStreamsProperties(t *testing.T) {
+
+	v := StreamsProperties(map[string]string{"auto.offset.reset": "auto_offset_reset"})
+
+	tests := []struct {
+		key string
+		// warning is a part of the expected warning, empty if there is none
+		warning string
+	}{
+		{"ksql.query.pull.enabled", ""},
+		{"producer.acks", ""},
+		{"num.standby.replicas", ""},
+		{"auto.offset.reset", "Use the validated streams_properties.auto_offset_reset attribute"},
+		{"auto.ofset.reset", "Unknown streams property 'auto.ofset.reset'"},
+	}
+
+	for _, test := range tests {
+		request := validator.MapRequest{
+			Path:        path.Root("properties"),
+			ConfigValue: types.MapValueMust(types.StringType, map[string]attr.Value{test.key: types.StringValue("value")}),
+		}
+		response := validator.MapResponse{}
+
+		v.ValidateMap(context.Background(), request, &response)
+
+		if response.Diagnostics.HasError() {
+			t.Errorf("%s: expected only warnings, got %v", test.key, response.Diagnostics)
+		}
+
+		warnings := response.Diagnostics.Warnings()
+		if test.warning == "" && len(warnings) > 0 {
+			t.Errorf("%s: expected no warning, got %v", test.key, warnings)
+		}
+		if test.warning != "" && (len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), test.warning)) {
+			t.Errorf("%s: expected a warning with %q, got %v", test.key, test.warning, warnings)
+		}
+	}
+}
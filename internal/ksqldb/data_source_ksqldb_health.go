@@ -0,0 +1,146 @@
+package ksqldb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"sort"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &HealthDataSource{}
+
+func NewHealthDataSource() datasource.DataSource {
+	return &HealthDataSource{}
+}
+
+// HealthDataSource defines the data source implementation.
+type HealthDataSource struct {
+	client *Client
+}
+
+type HealthDataSourceModel struct {
+	IsHealthy types.Bool  `tfsdk:"is_healthy"`
+	Details   types.Map   `tfsdk:"details"`
+	Hosts     []HostModel `tfsdk:"hosts"`
+}
+
+type HostModel struct {
+	Host               types.String `tfsdk:"host"`
+	Alive              types.Bool   `tfsdk:"alive"`
+	LastStatusUpdateMs types.Int64  `tfsdk:"last_status_update_ms"`
+}
+
+func (d *HealthDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_health"
+}
+
+func (d *HealthDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "The health of the ksqlDB server the provider is connected to and the status of the hosts in its cluster. An unhealthy server is not an error, so the result can be used in preconditions.",
+
+		Attributes: map[string]schema.Attribute{
+			"is_healthy": schema.BoolAttribute{
+				MarkdownDescription: "Whether the server and all of its components are healthy.",
+				Computed:            true,
+			},
+			"details": schema.MapAttribute{
+				MarkdownDescription: "The health of the individual components of the server, e.g. `metastore`, `kafka` and `commandRunner`.",
+				Computed:            true,
+				ElementType:         types.BoolType,
+			},
+			"hosts": schema.ListNestedAttribute{
+				MarkdownDescription: "The hosts of the ksqlDB cluster as seen by the server, ordered by host. Only populated if heartbeats are enabled on the server (`ksql.heartbeat.enable`).",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"host": schema.StringAttribute{
+							MarkdownDescription: "The host and port of the server.",
+							Computed:            true,
+						},
+						"alive": schema.BoolAttribute{
+							MarkdownDescription: "Whether the host is alive.",
+							Computed:            true,
+						},
+						"last_status_update_ms": schema.Int64Attribute{
+							MarkdownDescription: "The time of the last status update received from the host, in milliseconds since the epoch.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *HealthDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ksqldb.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *HealthDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data HealthDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	health, err := d.client.healthCheck(ctx)
+	if err != nil {
+		resp.Diagnostics.Append(diag.NewErrorDiagnostic(err.Error(), err.Error()))
+		return
+	}
+
+	hosts, err := d.client.clusterStatus(ctx)
+	if err != nil {
+		resp.Diagnostics.Append(diag.NewErrorDiagnostic(err.Error(), err.Error()))
+		return
+	}
+
+	details := make(map[string]bool, len(health.Details))
+	for component, detail := range health.Details {
+		details[component] = detail.IsHealthy
+	}
+
+	var diags diag.Diagnostics
+	data.IsHealthy = types.BoolValue(health.IsHealthy)
+	data.Details, diags = types.MapValueFrom(ctx, types.BoolType, details)
+	resp.Diagnostics.Append(diags...)
+
+	data.Hosts = make([]HostModel, 0, len(hosts))
+	for host, status := range hosts {
+		data.Hosts = append(data.Hosts, HostModel{
+			Host:               types.StringValue(host),
+			Alive:              types.BoolValue(status.HostAlive),
+			LastStatusUpdateMs: types.Int64Value(status.LastStatusUpdateMs),
+		})
+	}
+	sort.Slice(data.Hosts, func(i, j int) bool {
+		return data.Hosts[i].Host.ValueString() < data.Hosts[j].Host.ValueString()
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,29 @@
+package ksqldb
+
+import (
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"testing"
+)
+
+func TestAccHealthDataSource(t *testing.T) {
+	server := testAccFakeServer(t)
+	server.SetHealthy("kafka", false)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "ksqldb_health" "this" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ksqldb_health.this", "is_healthy", "false"),
+					resource.TestCheckResourceAttr("data.ksqldb_health.this", "details.kafka", "false"),
+					resource.TestCheckResourceAttr("data.ksqldb_health.this", "details.metastore", "true"),
+					resource.TestCheckResourceAttr("data.ksqldb_health.this", "hosts.#", "1"),
+					resource.TestCheckResourceAttr("data.ksqldb_health.this", "hosts.0.alive", "true"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,130 @@
+package ksqldb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strconv"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &SchemaDataSource{}
+
+func NewSchemaDataSource() datasource.DataSource {
+	return &SchemaDataSource{}
+}
+
+// SchemaDataSource defines the data source implementation.
+type SchemaDataSource struct {
+	client *Client
+}
+
+type SchemaDataSourceModel struct {
+	Subject    types.String `tfsdk:"subject"`
+	Version    types.Int64  `tfsdk:"version"`
+	Id         types.Int64  `tfsdk:"id"`
+	SchemaType types.String `tfsdk:"schema_type"`
+	Schema     types.String `tfsdk:"schema"`
+}
+
+func (d *SchemaDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_schema"
+}
+
+func (d *SchemaDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Resolves a subject and version in Schema Registry to the schema ID, e.g. for the `key_schema_id` and `value_schema_id` attributes of a stream. Requires the `schema_registry` provider configuration.",
+
+		Attributes: map[string]schema.Attribute{
+			"subject": schema.StringAttribute{
+				MarkdownDescription: "The subject the schema is registered under, e.g. `orders-value`.",
+				Required:            true,
+			},
+			"version": schema.Int64Attribute{
+				MarkdownDescription: "The version of the schema in the subject. Defaults to the latest version.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"id": schema.Int64Attribute{
+				MarkdownDescription: "The globally unique ID of the schema.",
+				Computed:            true,
+			},
+			"schema_type": schema.StringAttribute{
+				MarkdownDescription: "The type of the schema, one of `AVRO`, `JSON` or `PROTOBUF`.",
+				Computed:            true,
+			},
+			"schema": schema.StringAttribute{
+				MarkdownDescription: "The schema definition.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *SchemaDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ksqldb.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *SchemaDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SchemaDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if d.client.schemaRegistry == nil {
+		resp.Diagnostics.AddError(
+			"Missing Schema Registry Configuration",
+			"The ksqldb_schema data source requires the schema_registry provider configuration "+
+				"or the KSQLDB_SCHEMA_REGISTRY_URL environment variable.",
+		)
+		return
+	}
+
+	version := "latest"
+	if !data.Version.IsNull() && !data.Version.IsUnknown() {
+		version = strconv.FormatInt(data.Version.ValueInt64(), 10)
+	}
+
+	found, err := d.client.schemaRegistry.getSchema(ctx, data.Subject.ValueString(), version)
+	if err != nil {
+		resp.Diagnostics.Append(diag.NewErrorDiagnostic(err.Error(), err.Error()))
+		return
+	}
+
+	data.Version = types.Int64Value(found.Version)
+	data.Id = types.Int64Value(found.Id)
+	data.SchemaType = types.StringValue(found.SchemaType)
+	data.Schema = types.StringValue(found.Schema)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
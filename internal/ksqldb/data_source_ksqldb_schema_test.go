@@ -0,0 +1,61 @@
+package ksqldb
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAccSchemaDataSource(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	schemaRegistry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+		switch r.URL.Path {
+		case "/subjects/orders-value/versions/latest":
+			_, _ = w.Write([]byte(`{"subject":"orders-value","version":2,"id":12,"schema":"{\"type\":\"string\"}"}`))
+		case "/subjects/orders-value/versions/1":
+			_, _ = w.Write([]byte(`{"subject":"orders-value","version":1,"id":7,"schemaType":"PROTOBUF","schema":"syntax = \"proto3\";"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code":40401,"message":"Subject 'unknown' not found."}`))
+		}
+	}))
+	t.Cleanup(schemaRegistry.Close)
+
+	config := fmt.Sprintf(`
+provider "ksqldb" {
+  url = %q
+  schema_registry = {
+    url = %q
+  }
+}
+`, server.URL, schemaRegistry.URL)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config + `
+data "ksqldb_schema" "latest" {
+  subject = "orders-value"
+}
+
+data "ksqldb_schema" "pinned" {
+  subject = "orders-value"
+  version = 1
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ksqldb_schema.latest", "id", "12"),
+					resource.TestCheckResourceAttr("data.ksqldb_schema.latest", "version", "2"),
+					resource.TestCheckResourceAttr("data.ksqldb_schema.latest", "schema_type", "AVRO"),
+					resource.TestCheckResourceAttr("data.ksqldb_schema.pinned", "id", "7"),
+					resource.TestCheckResourceAttr("data.ksqldb_schema.pinned", "schema_type", "PROTOBUF"),
+				),
+			},
+		},
+	})
+}
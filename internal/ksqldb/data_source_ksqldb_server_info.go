@@ -0,0 +1,104 @@
+package ksqldb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ServerInfoDataSource{}
+
+func NewServerInfoDataSource() datasource.DataSource {
+	return &ServerInfoDataSource{}
+}
+
+// ServerInfoDataSource defines the data source implementation.
+type ServerInfoDataSource struct {
+	client *Client
+}
+
+type ServerInfoDataSourceModel struct {
+	Version        types.String `tfsdk:"version"`
+	KafkaClusterId types.String `tfsdk:"kafka_cluster_id"`
+	KsqlServiceId  types.String `tfsdk:"ksql_service_id"`
+	ServerStatus   types.String `tfsdk:"server_status"`
+}
+
+func (d *ServerInfoDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_server_info"
+}
+
+func (d *ServerInfoDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Information about the ksqlDB server the provider is connected to, e.g. to assert the server version in a module.",
+
+		Attributes: map[string]schema.Attribute{
+			"version": schema.StringAttribute{
+				MarkdownDescription: "The version of the ksqlDB server, e.g. `0.29.0`.",
+				Computed:            true,
+			},
+			"kafka_cluster_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the Kafka cluster the server is connected to.",
+				Computed:            true,
+			},
+			"ksql_service_id": schema.StringAttribute{
+				MarkdownDescription: "The service ID of the ksqlDB cluster the server belongs to.",
+				Computed:            true,
+			},
+			"server_status": schema.StringAttribute{
+				MarkdownDescription: "The status of the server, e.g. `RUNNING`.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *ServerInfoDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ksqldb.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *ServerInfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ServerInfoDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	info, err := d.client.serverInfo(ctx)
+	if err != nil {
+		resp.Diagnostics.Append(diag.NewErrorDiagnostic(err.Error(), err.Error()))
+		return
+	}
+
+	data.Version = types.StringValue(info.Version)
+	data.KafkaClusterId = types.StringValue(info.KafkaClusterId)
+	data.KsqlServiceId = types.StringValue(info.KsqlServiceId)
+	data.ServerStatus = types.StringValue(info.ServerStatus)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
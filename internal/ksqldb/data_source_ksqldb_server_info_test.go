@@ -0,0 +1,28 @@
+package ksqldb
+
+import (
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"testing"
+)
+
+func TestAccServerInfoDataSource(t *testing.T) {
+	server := testAccFakeServer(t)
+	server.SetVersion("0.28.2")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "ksqldb_server_info" "this" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ksqldb_server_info.this", "version", "0.28.2"),
+					resource.TestCheckResourceAttr("data.ksqldb_server_info.this", "kafka_cluster_id", "fake-kafka-cluster"),
+					resource.TestCheckResourceAttr("data.ksqldb_server_info.this", "ksql_service_id", "default_"),
+					resource.TestCheckResourceAttr("data.ksqldb_server_info.this", "server_status", "RUNNING"),
+				),
+			},
+		},
+	})
+}
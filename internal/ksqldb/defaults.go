@@ -0,0 +1,50 @@
+package ksqldb
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// StreamDefaults are provider-level defaults for attributes of streams which are not set by a resource.
+type StreamDefaults struct {
+	KeyFormat         string
+	ValueFormat       string
+	Replicas          *int64
+	StreamsProperties map[string]string
+}
+
+// apply returns the stream with the defaults set for each attribute the resource doesn't specify.
+func (d StreamDefaults) apply(data StreamResourceModel) StreamResourceModel {
+
+	if isUnset(data.KeyFormat) && d.KeyFormat != "" {
+		data.KeyFormat = types.StringValue(d.KeyFormat)
+	}
+	if isUnset(data.ValueFormat) && d.ValueFormat != "" {
+		data.ValueFormat = types.StringValue(d.ValueFormat)
+	}
+	if isUnset(data.Replicas) && d.Replicas != nil {
+		data.Replicas = types.Int64Value(*d.Replicas)
+	}
+
+	return data
+}
+
+// streamsProperties merges the default streams properties with the properties of a resource, which take precedence.
+func (d StreamDefaults) streamsProperties(properties map[string]string) map[string]string {
+
+	merged := make(map[string]string, len(d.StreamsProperties)+len(properties))
+	for key, value := range d.StreamsProperties {
+		merged[key] = value
+	}
+	for key, value := range properties {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+func isUnset(value interface {
+	IsNull() bool
+	IsUnknown() bool
+}) bool {
+	return value.IsNull() || value.IsUnknown()
+}
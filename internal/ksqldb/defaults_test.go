@@ -0,0 +1,80 @@
+package ksqldb
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"testing"
+)
+
+func TestStreamDefaultsApply(t *testing.T) {
+
+	replicas := int64(3)
+	defaults := StreamDefaults{KeyFormat: "KAFKA", ValueFormat: "AVRO", Replicas: &replicas}
+
+	tests := []struct {
+		name     string
+		defaults StreamDefaults
+		change   func(data *StreamResourceModel)
+		want     func(data *StreamResourceModel)
+	}{
+		{"unset attributes", defaults, func(d *StreamResourceModel) {}, func(d *StreamResourceModel) {
+			d.KeyFormat = types.StringValue("KAFKA")
+			d.ValueFormat = types.StringValue("AVRO")
+			d.Replicas = types.Int64Value(3)
+		}},
+		{"unknown attributes", defaults, func(d *StreamResourceModel) {
+			d.KeyFormat = types.StringUnknown()
+			d.Replicas = types.Int64Unknown()
+		}, func(d *StreamResourceModel) {
+			d.KeyFormat = types.StringValue("KAFKA")
+			d.ValueFormat = types.StringValue("AVRO")
+			d.Replicas = types.Int64Value(3)
+		}},
+		{"configured attributes", defaults, func(d *StreamResourceModel) {
+			d.KeyFormat = types.StringValue("JSON")
+			d.ValueFormat = types.StringValue("PROTOBUF")
+			d.Replicas = types.Int64Value(1)
+		}, func(d *StreamResourceModel) {
+			d.KeyFormat = types.StringValue("JSON")
+			d.ValueFormat = types.StringValue("PROTOBUF")
+			d.Replicas = types.Int64Value(1)
+		}},
+		{"no defaults", StreamDefaults{}, func(d *StreamResourceModel) {}, func(d *StreamResourceModel) {}},
+	}
+
+	for _, test := range tests {
+		data := emptyStream("ORDERS")
+		test.change(&data)
+
+		want := emptyStream("ORDERS")
+		test.want(&want)
+
+		if got := test.defaults.apply(data); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %+v, got %+v", test.name, want, got)
+		}
+	}
+}
+
+func TestStreamDefaultsStreamsProperties(t *testing.T) {
+
+	defaults := StreamDefaults{StreamsProperties: map[string]string{
+		"auto.offset.reset":        "earliest",
+		"ksql.streams.num.threads": "2",
+	}}
+
+	got := defaults.streamsProperties(map[string]string{"auto.offset.reset": "latest"})
+
+	want := map[string]string{"auto.offset.reset": "latest", "ksql.streams.num.threads": "2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// the defaults are not changed by merging
+	if defaults.StreamsProperties["auto.offset.reset"] != "earliest" {
+		t.Errorf("expected the defaults to be kept, got %v", defaults.StreamsProperties)
+	}
+
+	if got := (StreamDefaults{}).streamsProperties(nil); len(got) != 0 {
+		t.Errorf("expected no properties, got %v", got)
+	}
+}
@@ -0,0 +1,173 @@
+package ksqldb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"terraform-provider-ksqldb/internal/ksqldb/parser"
+)
+
+// readOnlyStatements are statements which don't change anything in ksqlDB and can therefore be retried on
+// another node even if they may have reached the failed node.
+var readOnlyStatements = []string{"DESCRIBE", "EXPLAIN", "LIST", "SHOW"}
+
+// splitUrls splits a comma separated list of URLs, e.g. from the KSQLDB_URL environment variable.
+func splitUrls(urls string) []string {
+	var result []string
+	for _, url := range strings.Split(urls, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			result = append(result, url)
+		}
+	}
+	return result
+}
+
+// send sends a request to the nodes of the cluster, beginning with the preferred node, until one of them
+// responds. A request which may have reached a node is only sent to the next node if it is read-only, since
+// all nodes share the command topic and a statement would otherwise be executed twice.
+func (c *Client) send(ctx context.Context, readOnly bool, newRequest func(url string) (*http.Request, error)) (*http.Response, error) {
+
+	nodes := c.nodes(ctx)
+	if len(nodes) == 0 {
+		return nil, errors.New("no ksqlDB URL configured")
+	}
+
+	var lastErr error
+
+	for i, url := range nodes {
+		req, err := newRequest(url)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := c.client.Do(req)
+		if err == nil {
+			c.prefer(url)
+			return res, nil
+		}
+
+		lastErr = err
+
+		if ctx.Err() != nil || i == len(nodes)-1 {
+			break
+		}
+
+		if !readOnly && !isNotSent(err) {
+			return nil, fmt.Errorf("not failing over to another node since the statement may have been executed by %s: %w", url, err)
+		}
+
+		tflog.Warn(ctx, fmt.Sprintf("Request to %s failed, failing over to the next node: %s", url, err))
+	}
+
+	return nil, lastErr
+}
+
+// nodes returns the URLs of the nodes in the order they should be tried. Before the first request to a
+// cluster, the nodes are health-checked and the first healthy node is preferred.
+func (c *Client) nodes(ctx context.Context) []string {
+
+	c.nodeMutex.Lock()
+	defer c.nodeMutex.Unlock()
+
+	if !c.healthChecked && len(c.urls) > 1 {
+		c.healthChecked = true
+		c.preferred = c.healthyNode(ctx)
+	}
+
+	nodes := make([]string, 0, len(c.urls))
+	for i := range c.urls {
+		nodes = append(nodes, c.urls[(c.preferred+i)%len(c.urls)])
+	}
+
+	return nodes
+}
+
+// prefer sends subsequent requests to the given node first.
+func (c *Client) prefer(url string) {
+
+	c.nodeMutex.Lock()
+	defer c.nodeMutex.Unlock()
+
+	for i, u := range c.urls {
+		if u == url {
+			c.preferred = i
+			return
+		}
+	}
+}
+
+// healthyNode returns the index of the first node which reports to be healthy, or 0 if there is none.
+func (c *Client) healthyNode(ctx context.Context) int {
+
+	for i, url := range c.urls {
+		req, err := c.newGetRequest(ctx, url, "/healthcheck")
+		if err != nil {
+			continue
+		}
+
+		res, err := c.client.Do(req)
+		if err != nil {
+			tflog.Warn(ctx, fmt.Sprintf("Health check of %s failed: %s", url, err))
+			continue
+		}
+
+		var health HealthCheck
+		body, err := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err == nil && res.StatusCode == http.StatusOK && json.Unmarshal(body, &health) == nil && health.IsHealthy {
+			return i
+		}
+
+		tflog.Warn(ctx, fmt.Sprintf("Node %s is not healthy: %s", url, string(body)))
+	}
+
+	return 0
+}
+
+// isNotSent returns whether the error proves that the request never reached the server, i.e. the connection
+// could not be established.
+func isNotSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isReadOnlyStatement returns whether every statement of the text is read-only. Texts which can't be split
+// into statements are not.
+func isReadOnlyStatement(ksql string) bool {
+
+	statements, err := parser.SplitStatements(ksql)
+	if err != nil || len(statements) == 0 {
+		return false
+	}
+
+	for _, statement := range statements {
+		if !isReadOnly(statement) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isReadOnly returns whether the single statement is read-only.
+func isReadOnly(statement string) bool {
+
+	tokens, err := parser.Tokenize(statement)
+	if err != nil || len(tokens) == 0 {
+		return false
+	}
+
+	for _, keyword := range readOnlyStatements {
+		if tokens[0].Is(keyword) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,258 @@
+package ksqldb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"terraform-provider-ksqldb/internal/ksqldb/util"
+	"unicode"
+)
+
+var resourceNameInvalidChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// processingLogStream is created and managed by ksqlDB itself.
+const processingLogStream = "KSQL_PROCESSING_LOG"
+
+// GenerateImports writes resource and import blocks for all streams found in ksqlDB to w. The connection is
+// configured by the same environment variables as the provider. Tables, types and persistent queries which
+// can't be managed by the provider yet are written as comments containing their statements. The version of the
+// provider is sent in the user agent like the provider does.
+func GenerateImports(ctx context.Context, version string, w io.Writer) error {
+
+	client, diags := newClientFromConfig(ctx, version, KsqldbProviderModel{Urls: types.ListNull(types.StringType), DefaultStreamsProperties: types.MapNull(types.StringType)})
+	if diags.HasError() {
+		return fmt.Errorf("%s: %s", diags[0].Summary(), diags[0].Detail())
+	}
+
+	g := &generator{w: w, names: map[string]bool{}}
+
+	streams, err := client.list(ctx, "SHOW STREAMS;")
+	if err != nil {
+		return err
+	}
+
+	// queries which materialize a generated stream are part of the stream resource
+	managedSinks := map[string]bool{}
+
+	for _, stream := range sortedSources(streams.Streams) {
+		if stream.Name == processingLogStream {
+			continue
+		}
+
+		data := StreamResourceModel{
+			Name: types.StringValue(util.QuoteIdentifier(stream.Name)),
+		}
+
+		if err := doReadInternal(ctx, &data, client); err != nil {
+			return fmt.Errorf("could not describe stream %s: %w", stream.Name, err)
+		}
+
+		if !data.Query.IsNull() {
+			managedSinks[stream.Name] = true
+		}
+
+		g.stream(data)
+	}
+
+	tables, err := client.list(ctx, "SHOW TABLES;")
+	if err != nil {
+		return err
+	}
+
+	for _, table := range sortedSources(tables.Tables) {
+		source, err := client.describe(ctx, util.QuoteIdentifier(table.Name))
+		if err != nil {
+			return fmt.Errorf("could not describe table %s: %w", table.Name, err)
+		}
+
+		g.unsupported(fmt.Sprintf("Table %s", table.Name), source.Statement)
+		managedSinks[table.Name] = true
+	}
+
+	typeList, err := client.list(ctx, "SHOW TYPES;")
+	if err != nil {
+		return err
+	}
+
+	typeNames := make([]string, 0, len(typeList.Types))
+	for name := range typeList.Types {
+		typeNames = append(typeNames, name)
+	}
+	sort.Strings(typeNames)
+
+	for _, name := range typeNames {
+		g.unsupported(fmt.Sprintf("Type %s", name), string(typeList.Types[name]))
+	}
+
+	queries, err := client.list(ctx, "SHOW QUERIES;")
+	if err != nil {
+		return err
+	}
+
+	for _, query := range queries.Queries {
+		if query.QueryType != "" && query.QueryType != "PERSISTENT" {
+			continue
+		}
+
+		// CSAS and CTAS queries are covered by their stream or table, but e.g. INSERT INTO queries are not
+		if len(query.Sinks) == 1 && managedSinks[query.Sinks[0]] && !isInsertQuery(query.QueryString) {
+			continue
+		}
+
+		g.unsupported(fmt.Sprintf("Persistent query %s", query.Id), query.QueryString)
+	}
+
+	return g.err
+}
+
+func sortedSources(sources []SourceSummary) []SourceSummary {
+	sorted := append([]SourceSummary(nil), sources...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func isInsertQuery(query string) bool {
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(query)), "INSERT ")
+}
+
+// generator writes HCL and remembers the first write error.
+type generator struct {
+	w     io.Writer
+	names map[string]bool
+	err   error
+}
+
+func (g *generator) printf(format string, args ...any) {
+	if g.err != nil {
+		return
+	}
+	_, g.err = fmt.Fprintf(g.w, format, args...)
+}
+
+func (g *generator) stream(data StreamResourceModel) {
+
+	name := g.resourceName(data.Name.ValueString())
+
+	g.printf("resource \"ksqldb_stream\" %q {\n", name)
+	g.attribute("name", data.Name)
+	g.attribute("kafka_topic", data.KafkaTopic)
+	g.attribute("key_format", data.KeyFormat)
+	g.attribute("value_format", data.ValueFormat)
+	g.attribute("key_schema_id", data.KeySchemaId)
+	g.attribute("value_schema_id", data.ValueSchemaId)
+	g.attribute("retention_ms", data.Retention)
+	g.attribute("timestamp", data.Timestamp)
+	g.attribute("timestamp_format", data.TimestampFormat)
+	if data.Source.ValueBool() {
+		g.attribute("source", data.Source)
+	}
+	g.attribute("query", data.Query)
+	g.attribute("properties", data.Properties)
+	g.printf("}\n\n")
+
+	g.printf("import {\n")
+	g.printf("  to = ksqldb_stream.%s\n", name)
+	g.printf("  id = %s\n", hclString(data.Name.ValueString()))
+	g.printf("}\n\n")
+}
+
+func (g *generator) attribute(key string, value any) {
+	switch v := value.(type) {
+	case types.String:
+		if !v.IsNull() && !v.IsUnknown() {
+			g.printf("  %s = %s\n", key, hclString(v.ValueString()))
+		}
+	case types.Int64:
+		if !v.IsNull() && !v.IsUnknown() {
+			g.printf("  %s = %d\n", key, v.ValueInt64())
+		}
+	case types.Bool:
+		if !v.IsNull() && !v.IsUnknown() {
+			g.printf("  %s = %t\n", key, v.ValueBool())
+		}
+	case types.Map:
+		if v.IsNull() || v.IsUnknown() {
+			return
+		}
+		keys := make([]string, 0, len(v.Elements()))
+		for k := range v.Elements() {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		g.printf("  %s = {\n", key)
+		for _, k := range keys {
+			if s, ok := v.Elements()[k].(types.String); ok {
+				g.printf("    %s = %s\n", hclString(k), hclString(s.ValueString()))
+			}
+		}
+		g.printf("  }\n")
+	}
+}
+
+func (g *generator) unsupported(what string, statement string) {
+	g.printf("# %s can't be managed by the provider yet:\n", what)
+	for _, line := range strings.Split(strings.TrimSpace(statement), "\n") {
+		g.printf("#   %s\n", line)
+	}
+	g.printf("\n")
+}
+
+// resourceName derives a unique Terraform resource name from the name of a ksqlDB object.
+func (g *generator) resourceName(name string) string {
+
+	base := resourceNameInvalidChars.ReplaceAllString(strings.ToLower(util.NormalizeIdentifier(name)), "_")
+	base = strings.Trim(base, "_")
+	if base == "" || (base[0] >= '0' && base[0] <= '9') {
+		base = "stream_" + base
+	}
+
+	unique := base
+	for i := 2; g.names[unique]; i++ {
+		unique = base + "_" + strconv.Itoa(i)
+	}
+	g.names[unique] = true
+
+	return unique
+}
+
+// hclString quotes a string as HCL string literal. Unlike strconv.Quote, it only uses the escape sequences
+// HCL supports, and it escapes template sequences.
+func hclString(v string) string {
+
+	var sb strings.Builder
+	sb.WriteByte('"')
+
+	for i, r := range v {
+		switch {
+		case r == '"' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case (r == '$' || r == '%') && strings.HasPrefix(v[i+1:], "{"):
+			// ${ and %{ start template sequences, doubling the first character makes them literal
+			sb.WriteRune(r)
+			sb.WriteRune(r)
+		case !unicode.IsPrint(r) && r > 0xFFFF:
+			fmt.Fprintf(&sb, `\U%08X`, r)
+		case !unicode.IsPrint(r):
+			fmt.Fprintf(&sb, `\u%04X`, r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	sb.WriteByte('"')
+	return sb.String()
+}
@@ -0,0 +1,233 @@
+package ksqldb
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"terraform-provider-ksqldb/internal/ksqldb/parser"
+	"terraform-provider-ksqldb/internal/ksqldb/util"
+)
+
+// migratedObject is an object which exists after all migrations have been applied.
+type migratedObject struct {
+	statement *parser.Statement
+	// properties are the streams properties which were set when the object was created
+	properties map[string]string
+	// inserts are the INSERT INTO statements which write to the object
+	inserts []string
+	// sequence orders the objects by the migration which created them
+	sequence int
+}
+
+// migrationState replays migrations in order to compute the objects which exist afterwards.
+type migrationState struct {
+	objects     map[string]*migratedObject
+	properties  map[string]string
+	unsupported []string
+	sequence    int
+}
+
+// GenerateImportsFromMigrations writes resource and import blocks for all streams which exist after applying
+// the migrations of a ksql-migrations directory to w. Like GenerateImports, tables, types and queries which
+// can't be managed by the provider yet are written as comments containing their statements.
+func GenerateImportsFromMigrations(directory string, w io.Writer) error {
+
+	files, err := migrationFiles(directory)
+	if err != nil {
+		return err
+	}
+
+	state := &migrationState{objects: map[string]*migratedObject{}, properties: map[string]string{}}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		statements, err := parser.SplitStatements(string(content))
+		if err != nil {
+			return fmt.Errorf("could not parse %s: %w", filepath.Base(file), err)
+		}
+
+		for _, text := range statements {
+			statement, err := parser.Parse(text)
+			if err != nil {
+				return fmt.Errorf("could not parse %s: %w", filepath.Base(file), err)
+			}
+			state.apply(statement)
+		}
+	}
+
+	g := &generator{w: w, names: map[string]bool{}}
+
+	for _, object := range state.sortedObjects() {
+		statement := object.statement
+
+		switch statement.ObjectType {
+		case "STREAM":
+			data, err := streamFromStatement(statement, object.properties)
+			if err != nil {
+				return fmt.Errorf("could not convert stream %s: %w", statement.Name, err)
+			}
+			g.stream(data)
+		case "TABLE":
+			g.unsupported(fmt.Sprintf("Table %s", util.NormalizeIdentifier(statement.Name)), statement.Text+";")
+		case "TYPE":
+			g.unsupported(fmt.Sprintf("Type %s", util.NormalizeIdentifier(statement.Name)), statement.Text+";")
+		}
+
+		for _, insert := range object.inserts {
+			g.unsupported(fmt.Sprintf("Query into %s", util.NormalizeIdentifier(statement.Name)), insert+";")
+		}
+	}
+
+	for _, text := range state.unsupported {
+		g.unsupported("Statement", text+";")
+	}
+
+	return g.err
+}
+
+// migrationFiles returns the migrations of the directory ordered by their version.
+func migrationFiles(directory string) ([]string, error) {
+
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := map[int]string{}
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil || entry.IsDir() {
+			continue
+		}
+		version, _ := strconv.Atoi(match[1])
+		if existing, ok := versions[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s have the same version", existing, entry.Name())
+		}
+		versions[version] = entry.Name()
+	}
+
+	sorted := make([]int, 0, len(versions))
+	for version := range versions {
+		sorted = append(sorted, version)
+	}
+	sort.Ints(sorted)
+
+	files := make([]string, 0, len(sorted))
+	for _, version := range sorted {
+		files = append(files, filepath.Join(directory, versions[version]))
+	}
+
+	return files, nil
+}
+
+func (s *migrationState) apply(statement *parser.Statement) {
+
+	name := util.NormalizeIdentifier(statement.Name)
+
+	switch {
+	case statement.Kind == "SET":
+		for key, value := range statement.Properties {
+			s.properties[key] = value
+		}
+	case statement.Kind == "UNSET":
+		for key := range statement.Properties {
+			delete(s.properties, key)
+		}
+	case statement.Kind == "CREATE" && isMigratedObjectType(statement.ObjectType):
+		object, ok := s.objects[name]
+		if !ok {
+			s.sequence++
+			object = &migratedObject{sequence: s.sequence}
+			s.objects[name] = object
+		}
+		object.statement = statement
+		object.properties = make(map[string]string, len(s.properties))
+		for key, value := range s.properties {
+			object.properties[key] = value
+		}
+	case statement.Kind == "DROP" && isMigratedObjectType(statement.ObjectType):
+		delete(s.objects, name)
+	case statement.Kind == "INSERT" && s.objects[name] != nil:
+		s.objects[name].inserts = append(s.objects[name].inserts, statement.Text)
+	default:
+		s.unsupported = append(s.unsupported, statement.Text)
+	}
+}
+
+func (s *migrationState) sortedObjects() []*migratedObject {
+	objects := make([]*migratedObject, 0, len(s.objects))
+	for _, object := range s.objects {
+		objects = append(objects, object)
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].sequence < objects[j].sequence
+	})
+	return objects
+}
+
+func isMigratedObjectType(objectType string) bool {
+	return objectType == "STREAM" || objectType == "TABLE" || objectType == "TYPE"
+}
+
+// streamFromStatement converts a CREATE STREAM statement to the configuration of a stream resource.
+func streamFromStatement(statement *parser.Statement, properties map[string]string) (StreamResourceModel, error) {
+
+	name := util.NormalizeIdentifier(statement.Name)
+
+	data := StreamResourceModel{
+		Name:            types.StringValue(util.QuoteIdentifier(name)),
+		KafkaTopic:      stringProperty(statement, "KAFKA_TOPIC"),
+		KeyFormat:       stringProperty(statement, "KEY_FORMAT"),
+		ValueFormat:     stringProperty(statement, "VALUE_FORMAT"),
+		Timestamp:       stringProperty(statement, "TIMESTAMP"),
+		TimestampFormat: stringProperty(statement, "TIMESTAMP_FORMAT"),
+		Source:          types.BoolValue(statement.Source),
+		Properties:      types.MapNull(types.StringType),
+	}
+
+	// ksqlDB names the topic of a stream after the stream unless the statement specifies it
+	if data.KafkaTopic.IsNull() {
+		data.KafkaTopic = types.StringValue(name)
+	}
+
+	// FORMAT sets both formats
+	if format, ok := statement.Properties["FORMAT"]; ok {
+		data.KeyFormat = types.StringValue(format)
+		data.ValueFormat = types.StringValue(format)
+	}
+
+	var err error
+	data.KeySchemaId, err = int64Property(statement, "KEY_SCHEMA_ID")
+	if err != nil {
+		return data, err
+	}
+	data.ValueSchemaId, err = int64Property(statement, "VALUE_SCHEMA_ID")
+	if err != nil {
+		return data, err
+	}
+	data.Retention, err = int64Property(statement, "RETENTION_MS")
+	if err != nil {
+		return data, err
+	}
+
+	setQuery(&data, statement)
+
+	if len(properties) > 0 {
+		elements := make(map[string]attr.Value, len(properties))
+		for key, value := range properties {
+			elements[key] = types.StringValue(value)
+		}
+		data.Properties = types.MapValueMust(types.StringType, elements)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,67 @@
+package ksqldb
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateImportsFromMigrations(t *testing.T) {
+	directory := t.TempDir()
+
+	migrations := map[string]string{
+		"V000001__create_stream_orders.sql": "CREATE STREAM ORDERS WITH (KAFKA_TOPIC='orders', FORMAT='AVRO');\n",
+		"V000002__create_stream_old.sql":    "CREATE STREAM OLD WITH (KAFKA_TOPIC='old', VALUE_FORMAT='JSON');\n",
+		"V000003__create_stream_large_orders.sql": "SET 'auto.offset.reset'='earliest';\n" +
+			"CREATE OR REPLACE STREAM large_orders AS SELECT * FROM ORDERS WHERE AMOUNT > 100;\n" +
+			"UNSET 'auto.offset.reset';\n" +
+			"INSERT INTO LARGE_ORDERS SELECT * FROM OLD;\n",
+		"V000004__drop_stream_old.sql": "-- no longer needed;\nDROP STREAM IF EXISTS OLD;\n",
+		"README.md":                    "not a migration",
+	}
+	for name, content := range migrations {
+		if err := os.WriteFile(filepath.Join(directory, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := GenerateImportsFromMigrations(directory, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `resource "ksqldb_stream" "orders" {
+  name = "ORDERS"
+  kafka_topic = "orders"
+  key_format = "AVRO"
+  value_format = "AVRO"
+}
+
+import {
+  to = ksqldb_stream.orders
+  id = "ORDERS"
+}
+
+resource "ksqldb_stream" "large_orders" {
+  name = "LARGE_ORDERS"
+  kafka_topic = "LARGE_ORDERS"
+  query = "SELECT * FROM ORDERS WHERE AMOUNT > 100"
+  properties = {
+    "auto.offset.reset" = "earliest"
+  }
+}
+
+import {
+  to = ksqldb_stream.large_orders
+  id = "LARGE_ORDERS"
+}
+
+# Query into LARGE_ORDERS can't be managed by the provider yet:
+#   INSERT INTO LARGE_ORDERS SELECT * FROM OLD;
+
+`
+	if out.String() != expected {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
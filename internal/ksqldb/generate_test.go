@@ -0,0 +1,103 @@
+package ksqldb
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestGenerateImports(t *testing.T) {
+	server := testAccFakeServer(t)
+	for _, ksql := range []string{
+		"CREATE STREAM ORDERS WITH (KAFKA_TOPIC='orders', KEY_FORMAT='AVRO', VALUE_FORMAT='AVRO', PARTITIONS=3);",
+		"CREATE STREAM `Orders v2` (ID STRING) WITH (KAFKA_TOPIC='orders_v2', VALUE_FORMAT='JSON', TIMESTAMP='ID');",
+		"CREATE STREAM LARGE_ORDERS AS SELECT * FROM ORDERS WHERE AMOUNT > 100 EMIT CHANGES;",
+		"CREATE TABLE TOTALS AS SELECT ID, SUM(AMOUNT) AS TOTAL FROM ORDERS GROUP BY ID EMIT CHANGES;",
+	} {
+		if err := server.Exec(ksql); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Setenv("KSQLDB_URL", server.URL)
+	t.Setenv("KSQLDB_USERNAME", "")
+	t.Setenv("KSQLDB_PASSWORD", "")
+	t.Setenv("KSQLDB_SCHEMA_REGISTRY_URL", "")
+
+	var out bytes.Buffer
+	if err := GenerateImports(context.Background(), "1.2.3", &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `resource "ksqldb_stream" "large_orders" {
+  name = "LARGE_ORDERS"
+  kafka_topic = "LARGE_ORDERS"
+  key_format = "KAFKA"
+  value_format = "JSON"
+  query = "SELECT * FROM ORDERS WHERE AMOUNT > 100 EMIT CHANGES"
+}
+
+import {
+  to = ksqldb_stream.large_orders
+  id = "LARGE_ORDERS"
+}
+
+resource "ksqldb_stream" "orders" {
+  name = "ORDERS"
+  kafka_topic = "orders"
+  key_format = "AVRO"
+  value_format = "AVRO"
+}
+
+import {
+  to = ksqldb_stream.orders
+  id = "ORDERS"
+}
+
+resource "ksqldb_stream" "orders_v2" {
+  name = "` + "`Orders v2`" + `"
+  kafka_topic = "orders_v2"
+  key_format = "KAFKA"
+  value_format = "JSON"
+  timestamp = "ID"
+}
+
+import {
+  to = ksqldb_stream.orders_v2
+  id = "` + "`Orders v2`" + `"
+}
+
+# Table TOTALS can't be managed by the provider yet:
+#   CREATE TABLE TOTALS AS SELECT ID, SUM(AMOUNT) AS TOTAL FROM ORDERS GROUP BY ID EMIT CHANGES;
+
+`
+	if out.String() != expected {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+// Strings used to be quoted with strconv.Quote, whose escapes like \x1b and \a are invalid in HCL.
+func TestHCLString(t *testing.T) {
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"ORDERS", `"ORDERS"`},
+		{"`Orders v2`", "\"`Orders v2`\""},
+		{`say "hi"`, `"say \"hi\""`},
+		{`C:\temp`, `"C:\\temp"`},
+		{"SELECT *\n  FROM ORDERS\r\n\t", `"SELECT *\n  FROM ORDERS\r\n\t"`},
+		{"${var} %{if} $ % {}", `"$${var} %%{if} $ % {}"`},
+		{"$${var}", `"$$${var}"`},
+		{"\x1b[0m\a", `"\u001B[0m\u0007"`},
+		{"größe €", `"größe €"`},
+		{"\u2028\U000E0001", `"\u2028\U000E0001"`},
+	}
+
+	for _, test := range tests {
+		if got := hclString(test.value); got != test.want {
+			t.Errorf("%q: expected %s, got %s", test.value, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package ksqldb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultRequestTimeout is the timeout of requests to ksqlDB and the Schema Registry unless configured otherwise.
+const defaultRequestTimeout = 10 * time.Second
+
+// HTTPSettings configures the HTTP client used for requests to ksqlDB and the Schema Registry. Zero values keep
+// the defaults of net/http.
+type HTTPSettings struct {
+	Timeout time.Duration
+	// ProxyURL is used for all requests. If nil, the proxy is taken from the HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+	// environment variables.
+	ProxyURL *url.URL
+	// Headers are added to every request to ksqlDB, e.g. for an authenticating proxy.
+	Headers map[string]string
+	// UserAgent is prepended to the user agent of the provider.
+	UserAgent           string
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+}
+
+// newHTTPClient creates an HTTP client with the given settings.
+func newHTTPClient(settings HTTPSettings) *http.Client {
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if settings.ProxyURL != nil {
+		transport.Proxy = http.ProxyURL(settings.ProxyURL)
+	}
+	if settings.MaxIdleConns > 0 {
+		transport.MaxIdleConns = settings.MaxIdleConns
+	}
+	if settings.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = settings.MaxIdleConnsPerHost
+	}
+	if settings.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = settings.MaxConnsPerHost
+	}
+
+	timeout := settings.Timeout
+	if timeout == 0 {
+		timeout = defaultRequestTimeout
+	}
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// userAgent returns the user agent of the provider, prefixed with the configured one.
+func userAgent(configured string, version string) string {
+	agent := fmt.Sprintf("terraform-provider-ksqldb/%s", version)
+	if configured = strings.TrimSpace(configured); configured != "" {
+		agent = configured + " " + agent
+	}
+	return agent
+}
+
+// configureHTTP applies the settings to the client and its Schema Registry client.
+func (c *Client) configureHTTP(settings HTTPSettings, version string) {
+
+	c.client = newHTTPClient(settings)
+	c.headers = settings.Headers
+	c.userAgent = userAgent(settings.UserAgent, version)
+
+	if c.schemaRegistry != nil {
+		c.schemaRegistry.client = c.client
+		c.schemaRegistry.userAgent = c.userAgent
+	}
+}
+
+// setHeaders sets the headers which are common to all requests to ksqlDB. Configured headers may replace the
+// Authorization header, e.g. to use a bearer token instead of basic authentication.
+func (c *Client) setHeaders(req *http.Request) {
+
+	req.SetBasicAuth(c.username, c.password)
+
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	// set headers according to ksqlDB HTTP API Reference: https://docs.ksqldb.io/en/latest/developer-guide/api/#content-types
+	req.Header.Set("Accept", "application/vnd.ksql.v1+json")
+}
@@ -0,0 +1,52 @@
+package ksqldb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"regexp"
+	"strings"
+	"terraform-provider-ksqldb/internal/ksqldb/util"
+)
+
+var importIdPattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// importSource imports a stream or table by its name. Names enclosed by backticks are imported as they are,
+// all others are converted to uppercase like ksqlDB does. The source must exist and be of the given type.
+func importSource(ctx context.Context, client *Client, sourceType string, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	id := strings.TrimSpace(req.ID)
+
+	if !util.IsBackTicked(id) {
+		if !importIdPattern.MatchString(id) {
+			resp.Diagnostics.Append(diag.NewErrorDiagnostic(
+				"Invalid name",
+				fmt.Sprintf("The name '%s' must only contain letters, numbers or underscore if it is not enclosed by backticks.", id),
+			))
+			return
+		}
+		id = strings.ToUpper(id)
+	}
+
+	source, err := client.describe(ctx, id)
+	if err != nil {
+		resp.Diagnostics.Append(diag.NewErrorDiagnostic(
+			fmt.Sprintf("Cannot import %s", strings.ToLower(sourceType)),
+			fmt.Sprintf("Could not describe %s: %s", id, err.Error()),
+		))
+		return
+	}
+
+	if source.Type != sourceType {
+		resp.Diagnostics.Append(diag.NewErrorDiagnostic(
+			fmt.Sprintf("Cannot import %s", strings.ToLower(sourceType)),
+			fmt.Sprintf("%s is a %s, not a %s", id, strings.ToLower(source.Type), strings.ToLower(sourceType)),
+		))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), types.StringValue(id))...)
+}
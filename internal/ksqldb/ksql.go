@@ -52,11 +52,15 @@ func createStreamKsql(ctx context.Context, name string, source bool, materialize
 
 	ksql := sb.String()
 
-	tflog.Info(ctx, fmt.Sprintf("Created KSQL statement: %s", ksql))
+	tflog.Debug(ctx, "Created KSQL statement", map[string]interface{}{"ksql": ksql})
 
 	return &ksql
 }
 
+func dropStreamKsql(name string) string {
+	return fmt.Sprintf("DROP STREAM %s;", name)
+}
+
 func appendIfSpecified(sb *strings.Builder, property string, value attr.Value, length *int, lengthBeforeProperties int) {
 
 	if value == nil || value.IsNull() || value.IsUnknown() {
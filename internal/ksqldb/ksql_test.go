@@ -0,0 +1,185 @@
+package ksqldb
+
+import (
+	"context"
+	"flag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"terraform-provider-ksqldb/internal/ksqldb/parser"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata/golden")
+
+// streamProperties lists every attribute which is written to the WITH clause, in statement order.
+var streamProperties = []struct {
+	property string
+	set      func(data *StreamResourceModel)
+	value    string
+}{
+	{"KAFKA_TOPIC", func(d *StreamResourceModel) { d.KafkaTopic = types.StringValue("orders") }, "orders"},
+	{"PARTITIONS", func(d *StreamResourceModel) { d.Partitions = types.Int64Value(6) }, "6"},
+	{"REPLICAS", func(d *StreamResourceModel) { d.Replicas = types.Int64Value(3) }, "3"},
+	{"RETENTION_MS", func(d *StreamResourceModel) { d.Retention = types.Int64Value(604800000) }, "604800000"},
+	{"TIMESTAMP", func(d *StreamResourceModel) { d.Timestamp = types.StringValue("CREATED_AT") }, "CREATED_AT"},
+	{"TIMESTAMP_FORMAT", func(d *StreamResourceModel) { d.TimestampFormat = types.StringValue("yyyy-MM-dd''T''HH:mm:ssX") }, "yyyy-MM-dd''T''HH:mm:ssX"},
+	{"KEY_FORMAT", func(d *StreamResourceModel) { d.KeyFormat = types.StringValue("AVRO") }, "AVRO"},
+	{"VALUE_FORMAT", func(d *StreamResourceModel) { d.ValueFormat = types.StringValue("JSON_SR") }, "JSON_SR"},
+	{"KEY_SCHEMA_ID", func(d *StreamResourceModel) { d.KeySchemaId = types.Int64Value(6) }, "6"},
+	{"VALUE_SCHEMA_ID", func(d *StreamResourceModel) { d.ValueSchemaId = types.Int64Value(7) }, "7"},
+}
+
+func emptyStream(name string) StreamResourceModel {
+	return StreamResourceModel{
+		Name:               types.StringValue(name),
+		KafkaTopic:         types.StringNull(),
+		Partitions:         types.Int64Null(),
+		Replicas:           types.Int64Null(),
+		Retention:          types.Int64Null(),
+		KeyFormat:          types.StringNull(),
+		ValueFormat:        types.StringNull(),
+		KeySchemaId:        types.Int64Null(),
+		ValueSchemaId:      types.Int64Null(),
+		Timestamp:          types.StringNull(),
+		TimestampFormat:    types.StringNull(),
+		Source:             types.BoolValue(false),
+		Query:              types.StringNull(),
+		Properties:         types.MapNull(types.StringType),
+		StreamsProperties:  types.ObjectNull(streamsPropertiesAttributeTypes),
+		CheckCompatibility: types.BoolValue(false),
+		AdoptExisting:      types.BoolValue(false),
+	}
+}
+
+func TestCreateStreamKsqlGolden(t *testing.T) {
+
+	all := emptyStream("ORDERS")
+	for _, p := range streamProperties {
+		p.set(&all)
+	}
+
+	withUnknowns := emptyStream("ORDERS")
+	withUnknowns.KafkaTopic = types.StringValue("orders")
+	withUnknowns.Partitions = types.Int64Unknown()
+	withUnknowns.Replicas = types.Int64Unknown()
+	withUnknowns.ValueFormat = types.StringValue("AVRO")
+
+	quoted := emptyStream("`orders-v2`")
+	quoted.KafkaTopic = types.StringValue("orders-v2")
+	quoted.Timestamp = types.StringValue("`created-at`")
+	quoted.ValueFormat = types.StringValue("AVRO")
+
+	materialized := emptyStream("LARGE_ORDERS")
+	materialized.KafkaTopic = types.StringValue("large-orders")
+	materialized.ValueFormat = types.StringValue("AVRO")
+	materialized.Query = types.StringValue("SELECT * FROM ORDERS WHERE AMOUNT > 100 EMIT CHANGES")
+
+	tests := []struct {
+		name         string
+		data         StreamResourceModel
+		source       bool
+		materialized bool
+	}{
+		{name: "topic_only", data: withProperties("ORDERS", "KAFKA_TOPIC")},
+		{name: "formats", data: withProperties("ORDERS", "KAFKA_TOPIC", "KEY_FORMAT", "VALUE_FORMAT")},
+		{name: "schema_ids", data: withProperties("ORDERS", "KAFKA_TOPIC", "VALUE_FORMAT", "KEY_SCHEMA_ID", "VALUE_SCHEMA_ID")},
+		{name: "topic_settings", data: withProperties("ORDERS", "KAFKA_TOPIC", "PARTITIONS", "REPLICAS", "RETENTION_MS", "VALUE_FORMAT")},
+		{name: "timestamp", data: withProperties("ORDERS", "KAFKA_TOPIC", "VALUE_FORMAT", "TIMESTAMP", "TIMESTAMP_FORMAT")},
+		{name: "all_properties", data: all},
+		{name: "unknown_values", data: withUnknowns},
+		{name: "quoted_identifiers", data: quoted},
+		{name: "source", data: all, source: true},
+		{name: "materialized", data: materialized, materialized: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ksql := createStreamKsql(context.Background(), test.data.Name.ValueString(), test.source, test.materialized, test.data)
+			assertGolden(t, filepath.Join("testdata", "golden", test.name+".sql"), *ksql+"\n")
+		})
+	}
+}
+
+// TestCreateStreamKsqlPropertyCombinations checks every combination of properties for a well-formed
+// statement which contains exactly the specified properties in a stable order.
+func TestCreateStreamKsqlPropertyCombinations(t *testing.T) {
+
+	for mask := 0; mask < 1<<len(streamProperties); mask++ {
+		data := emptyStream("ORDERS")
+		var expected []string
+
+		for i, p := range streamProperties {
+			if mask&(1<<i) != 0 {
+				p.set(&data)
+				expected = append(expected, p.property)
+			}
+		}
+
+		for _, source := range []bool{false, true} {
+			ksql := *createStreamKsql(context.Background(), "ORDERS", source, false, data)
+
+			statement, err := parser.Parse(ksql)
+			if err != nil {
+				t.Fatalf("could not parse %s: %s", ksql, err)
+			}
+			if statement.Source != source || statement.OrReplace == source {
+				t.Errorf("unexpected mode in %s", ksql)
+			}
+			if len(statement.Properties) != len(expected) {
+				t.Errorf("expected properties %v in %s", expected, ksql)
+			}
+
+			last := -1
+			for i, p := range streamProperties {
+				if mask&(1<<i) == 0 {
+					continue
+				}
+				if statement.Properties[p.property] != p.value {
+					t.Errorf("expected %s = '%s' in %s", p.property, p.value, ksql)
+				}
+				index := strings.Index(ksql, " "+p.property+" = ")
+				if index < last {
+					t.Errorf("unexpected order of %s in %s", p.property, ksql)
+				}
+				last = index
+			}
+		}
+	}
+}
+
+func withProperties(name string, properties ...string) StreamResourceModel {
+	data := emptyStream(name)
+	for _, p := range streamProperties {
+		for _, property := range properties {
+			if p.property == property {
+				p.set(&data)
+			}
+		}
+	}
+	return data
+}
+
+func assertGolden(t *testing.T, path string, actual string) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read golden file, run the test with -update to create it: %s", err)
+	}
+
+	if string(expected) != actual {
+		t.Errorf("statement differs from %s, run the test with -update to accept the change\nexpected: %s\nactual:   %s", path, expected, actual)
+	}
+}
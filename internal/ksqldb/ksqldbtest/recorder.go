@@ -0,0 +1,275 @@
+package ksqldbtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// RecordEnv is the environment variable which switches recorders from replaying to recording. When it is set
+// to "1", requests are sent to the ksqlDB server configured by KSQLDB_URL, KSQLDB_USERNAME and KSQLDB_PASSWORD
+// and the cassette files are overwritten.
+const RecordEnv = "KSQLDB_RECORD"
+
+// replayUrl is the URL clients use when replaying. The host is never contacted.
+const replayUrl = "http://ksqldb.invalid:8088"
+
+const redacted = "REDACTED"
+
+// sensitiveKeyPattern matches property and header names whose values must not be written to cassettes.
+var sensitiveKeyPattern = regexp.MustCompile(`(?i)password|secret|credentials|token|jaas|authorization|api\.key|user\.info`)
+
+// sensitivePropertyPattern matches 'key'='value' pairs in KSQL statements, e.g. in connector configurations.
+var sensitivePropertyPattern = regexp.MustCompile(`('[^']*'\s*=\s*)'((?:[^']|'')*)'`)
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the part of a request which is recorded and matched on replay.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Body   string `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded response.
+type RecordedResponse struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body"`
+}
+
+// Recorder is an http.RoundTripper which records requests and their responses to a cassette file or
+// replays them from it. Replayed interactions are matched by method, path and body in recorded order,
+// so each recorded interaction is used exactly once. Cassettes may also be written by hand, e.g. for
+// responses which are hard to provoke from a real server.
+type Recorder struct {
+	t         testing.TB
+	path      string
+	recording bool
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewRecorder creates a Recorder for the given cassette file. In replay mode the cassette is loaded
+// immediately, in record mode it is written when the test finishes.
+func NewRecorder(t testing.TB, path string) *Recorder {
+	t.Helper()
+
+	r := &Recorder{
+		t:         t,
+		path:      path,
+		recording: os.Getenv(RecordEnv) == "1",
+		transport: http.DefaultTransport,
+	}
+
+	if r.recording {
+		t.Cleanup(r.save)
+		return r
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read cassette %s, record it by setting %s=1: %s", path, RecordEnv, err)
+	}
+	if err := json.Unmarshal(content, &r.interactions); err != nil {
+		t.Fatalf("could not parse cassette %s: %s", path, err)
+	}
+	r.used = make([]bool, len(r.interactions))
+
+	return r
+}
+
+// Connection returns the URL and credentials clients must use with this recorder. When recording,
+// these are taken from the environment, otherwise placeholders are returned.
+func (r *Recorder) Connection() (url string, username string, password string) {
+	if r.recording {
+		return os.Getenv("KSQLDB_URL"), os.Getenv("KSQLDB_USERNAME"), os.Getenv("KSQLDB_PASSWORD")
+	}
+	return replayUrl, "user", "password"
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	recorded := RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.RequestURI(),
+		Body:   redactBody(string(body)),
+	}
+
+	if r.recording {
+		return r.record(req, recorded)
+	}
+
+	return r.replay(req, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+
+	res, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	headers := map[string]string{}
+	for key := range res.Header {
+		if sensitiveKeyPattern.MatchString(key) || key == "Set-Cookie" || key == "Date" {
+			continue
+		}
+		headers[key] = res.Header.Get(key)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.interactions = append(r.interactions, Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Headers:    headers,
+			Body:       redactBody(string(body)),
+		},
+	})
+
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.used[i] || interaction.Request != recorded {
+			continue
+		}
+		r.used[i] = true
+
+		header := http.Header{}
+		for key, value := range interaction.Response.Headers {
+			header.Set(key, value)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no unused interaction in cassette %s matches %s %s %s", r.path, recorded.Method, recorded.Path, recorded.Body)
+}
+
+func (r *Recorder) save() {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	content, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(r.path), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(r.path, append(content, '\n'), 0o644)
+	}
+	if err != nil {
+		r.t.Errorf("could not write cassette %s: %s", r.path, err)
+	}
+}
+
+// redactBody removes credentials from a JSON request or response body. Values of sensitive keys are
+// replaced in JSON objects as well as in 'key'='value' pairs of KSQL statements.
+func redactBody(body string) string {
+
+	if body == "" {
+		return body
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return redactStatement(body)
+	}
+
+	// keep the body as it is unless something was redacted
+	original, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	content, err := json.Marshal(redactValue(value))
+	if err != nil || bytes.Equal(original, content) {
+		return body
+	}
+
+	return string(content)
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if _, ok := child.(string); ok && sensitiveKeyPattern.MatchString(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(child)
+		}
+		return v
+	case []any:
+		for i, child := range v {
+			v[i] = redactValue(child)
+		}
+		return v
+	case string:
+		return redactStatement(v)
+	}
+	return value
+}
+
+func redactStatement(statement string) string {
+	return sensitivePropertyPattern.ReplaceAllStringFunc(statement, func(pair string) string {
+		match := sensitivePropertyPattern.FindStringSubmatch(pair)
+		if !sensitiveKeyPattern.MatchString(match[1]) {
+			return pair
+		}
+		return match[1] + "'" + redacted + "'"
+	})
+}
@@ -0,0 +1,546 @@
+// Package ksqldbtest provides an in-process fake of the ksqlDB REST API for tests.
+package ksqldbtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"terraform-provider-ksqldb/internal/ksqldb/parser"
+	"terraform-provider-ksqldb/internal/ksqldb/util"
+)
+
+// Server is a fake ksqlDB server which keeps its metastore in memory. It implements the /ksql endpoint
+// for CREATE, DESCRIBE, DROP, SHOW/LIST and TERMINATE statements as well as the /info, /healthcheck,
+// /clusterStatus and /status endpoints.
+type Server struct {
+	*httptest.Server
+
+	mu              sync.Mutex
+	sources         map[string]*source
+	queries         map[string]*query
+	queryCounter    int
+	commandSequence int64
+	requests        []Request
+	version         string
+	unhealthy       map[string]bool
+	commands        map[string]bool
+	queueCommands   bool
+}
+
+// DefaultVersion is the ksqlDB version reported by the fake server unless changed with SetVersion.
+const DefaultVersion = "0.29.0"
+
+// healthCheckComponents are the components reported by the /healthcheck endpoint.
+var healthCheckComponents = []string{"metastore", "kafka", "commandRunner"}
+
+// Request is a request received by the fake server.
+type Request struct {
+	Ksql                  string            `json:"ksql"`
+	Properties            map[string]string `json:"streamsProperties"`
+	CommandSequenceNumber *int64            `json:"commandSequenceNumber,omitempty"`
+}
+
+type source struct {
+	name        string
+	sourceType  string
+	topic       string
+	keyFormat   string
+	valueFormat string
+	partitions  int64
+	replication int64
+	timestamp   string
+	statement   string
+}
+
+type query struct {
+	id        string
+	statement string
+	sink      string
+}
+
+// NewServer starts a new fake ksqlDB server. The caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		sources:   map[string]*source{},
+		queries:   map[string]*query{},
+		version:   DefaultVersion,
+		unhealthy: map[string]bool{},
+		commands:  map[string]bool{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ksql", s.handleKsql)
+	mux.HandleFunc("/info", s.handleInfo)
+	mux.HandleFunc("/healthcheck", s.handleHealthCheck)
+	mux.HandleFunc("/clusterStatus", s.handleClusterStatus)
+	mux.HandleFunc("/status/", s.handleStatus)
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Requests returns all requests which have been sent to the /ksql endpoint so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// SetVersion changes the ksqlDB version reported by the /info endpoint.
+func (s *Server) SetVersion(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version = version
+}
+
+// SetHealthy changes the health of a component reported by the /healthcheck endpoint, one of
+// "metastore", "kafka" or "commandRunner".
+func (s *Server) SetHealthy(component string, healthy bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unhealthy[component] = !healthy
+}
+
+// SetQueueCommands makes the server respond to statements before their commands are executed, so the
+// response reports the status QUEUED and clients need to poll the /status endpoint.
+func (s *Server) SetQueueCommands(queue bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queueCommands = queue
+}
+
+// Exec executes the given statements directly, e.g. to prepare objects which are not managed by a test.
+func (s *Server) Exec(ksql string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.execute(ksql)
+	if err != nil {
+		return fmt.Errorf("%s", err.Message)
+	}
+	return nil
+}
+
+// statementError is the error entity returned by ksqlDB for failed statements.
+type statementError struct {
+	Type          string `json:"@type"`
+	ErrorCode     int    `json:"error_code"`
+	Message       string `json:"message"`
+	StatementText string `json:"statementText"`
+	Entities      []any  `json:"entities"`
+	status        int
+}
+
+func badStatement(statement string, format string, args ...any) *statementError {
+	return &statementError{
+		Type:          "statement_error",
+		ErrorCode:     40001,
+		Message:       fmt.Sprintf(format, args...),
+		StatementText: statement,
+		Entities:      []any{},
+		status:        http.StatusBadRequest,
+	}
+}
+
+func (s *Server) handleKsql(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodPost {
+		writeJson(w, http.StatusMethodNotAllowed, map[string]any{"error_code": 40500, "message": "HTTP 405 Method Not Allowed"})
+		return
+	}
+
+	var request Request
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJson(w, http.StatusBadRequest, map[string]any{"error_code": 40000, "message": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, request)
+
+	entities, err := s.execute(request.Ksql)
+	if err != nil {
+		writeJson(w, err.status, err)
+		return
+	}
+
+	writeJson(w, http.StatusOK, entities)
+}
+
+func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJson(w, http.StatusOK, map[string]any{
+		"KsqlServerInfo": map[string]any{
+			"version":        s.version,
+			"kafkaClusterId": "fake-kafka-cluster",
+			"ksqlServiceId":  "default_",
+			"serverStatus":   "RUNNING",
+		},
+	})
+}
+
+func (s *Server) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	healthy := true
+	details := map[string]any{}
+	for _, component := range healthCheckComponents {
+		details[component] = map[string]any{"isHealthy": !s.unhealthy[component]}
+		healthy = healthy && !s.unhealthy[component]
+	}
+
+	// like ksqlDB, respond with 503 if any component is unhealthy
+	status := http.StatusOK
+	if !healthy {
+		status = http.StatusServiceUnavailable
+	}
+
+	writeJson(w, status, map[string]any{"isHealthy": healthy, "details": details})
+}
+
+func (s *Server) handleClusterStatus(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, map[string]any{
+		"clusterStatus": map[string]any{
+			r.Host: map[string]any{
+				"hostAlive":             true,
+				"lastStatusUpdateMs":    1700000000000,
+				"activeStandbyPerQuery": map[string]any{},
+				"hostStoreLags":         map[string]any{"stateStoreLags": map[string]any{}, "updateTimeMs": 1700000000000},
+			},
+		},
+	})
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commandId := strings.TrimPrefix(r.URL.Path, "/status/")
+	if !s.commands[commandId] {
+		writeJson(w, http.StatusNotFound, map[string]any{"error_code": 40400, "message": "Command not found"})
+		return
+	}
+
+	writeJson(w, http.StatusOK, map[string]any{"status": "SUCCESS", "message": "Command executed"})
+}
+
+func (s *Server) execute(ksql string) ([]any, *statementError) {
+
+	texts, err := parser.SplitStatements(ksql)
+	if err != nil {
+		return nil, badStatement(ksql, "line 1:1: %s", err.Error())
+	}
+
+	entities := make([]any, 0, len(texts))
+
+	for _, text := range texts {
+		entity, err := s.executeStatement(text + ";")
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+
+	return entities, nil
+}
+
+func (s *Server) executeStatement(text string) (any, *statementError) {
+
+	words := strings.Fields(strings.ToUpper(strings.TrimSuffix(text, ";")))
+
+	switch {
+	case len(words) >= 2 && words[0] == "DESCRIBE":
+		// the name may be a quoted identifier with spaces
+		tokens, err := parser.Tokenize(text)
+		if err != nil {
+			return nil, badStatement(text, "line 1:1: %s", err.Error())
+		}
+		return s.describe(text, tokens[1].Text)
+	case len(words) == 2 && (words[0] == "SHOW" || words[0] == "LIST"):
+		return s.show(text, words[1])
+	case len(words) >= 2 && words[0] == "TERMINATE":
+		return s.terminate(text, strings.Fields(strings.TrimSuffix(text, ";"))[1])
+	}
+
+	statement, err := parser.Parse(text)
+	if err != nil {
+		return nil, badStatement(text, "line 1:1: %s", err.Error())
+	}
+
+	switch {
+	case statement.Kind == "CREATE" && (statement.ObjectType == "STREAM" || statement.ObjectType == "TABLE"):
+		return s.create(text, statement)
+	case statement.Kind == "DROP" && (statement.ObjectType == "STREAM" || statement.ObjectType == "TABLE"):
+		return s.drop(text, statement)
+	}
+
+	return nil, badStatement(text, "line 1:1: Syntax Error\nUnknown statement '%s'", words[0])
+}
+
+func (s *Server) create(text string, statement *parser.Statement) (any, *statementError) {
+
+	name := util.NormalizeIdentifier(statement.Name)
+	existing, exists := s.sources[name]
+	sourceType := statement.ObjectType
+
+	if exists && (!statement.OrReplace || existing.sourceType != sourceType) {
+		return nil, badStatement(text, "Cannot add %s '%s': A %s with the same name already exists",
+			strings.ToLower(sourceType), name, strings.ToLower(existing.sourceType))
+	}
+
+	if statement.Query == "" && statement.Columns == "" && statement.Properties["VALUE_SCHEMA_ID"] == "" && !isSchemaInferenceFormat(statement) {
+		return nil, badStatement(text, "The statement does not define any columns.")
+	}
+
+	if _, ok := statement.Properties["KAFKA_TOPIC"]; !ok && statement.Query == "" {
+		return nil, badStatement(text, "Missing required property \"KAFKA_TOPIC\" which has no default value.")
+	}
+
+	created := &source{
+		name:        name,
+		sourceType:  sourceType,
+		topic:       property(statement, "KAFKA_TOPIC", name),
+		keyFormat:   property(statement, "KEY_FORMAT", property(statement, "FORMAT", "KAFKA")),
+		valueFormat: property(statement, "VALUE_FORMAT", property(statement, "FORMAT", "JSON")),
+		partitions:  int64Property(statement, "PARTITIONS", 1),
+		replication: int64Property(statement, "REPLICAS", 1),
+		timestamp:   util.NormalizeIdentifier(property(statement, "TIMESTAMP", "")),
+		statement:   text,
+	}
+	s.sources[name] = created
+
+	var queryId any
+	if statement.Query != "" && !exists {
+		s.queryCounter++
+		id := fmt.Sprintf("C%sAS_%s_%d", sourceType[:1], name, s.queryCounter)
+		s.queries[id] = &query{id: id, statement: text, sink: name}
+		queryId = id
+	}
+
+	action, message := "create", "created"
+	if exists {
+		action, message = "createOrReplace", "replaced"
+	}
+
+	return s.command(text, fmt.Sprintf("%s/`%s`/%s", strings.ToLower(sourceType), name, action), fmt.Sprintf("%s%s %s", sourceType[:1], strings.ToLower(sourceType[1:]), message), queryId), nil
+}
+
+// command records an executed command and returns its currentStatus entity.
+func (s *Server) command(text string, commandId string, message string, queryId any) map[string]any {
+
+	s.commandSequence++
+	s.commands[commandId] = true
+
+	status := "SUCCESS"
+	if s.queueCommands {
+		status = "QUEUED"
+	}
+
+	return map[string]any{
+		"@type":         "currentStatus",
+		"statementText": text,
+		"commandId":     commandId,
+		"commandStatus": map[string]any{
+			"status":  status,
+			"message": message,
+			"queryId": queryId,
+		},
+		"commandSequenceNumber": s.commandSequence,
+		"warnings":              []any{},
+	}
+}
+
+func (s *Server) describe(text string, rawName string) (any, *statementError) {
+
+	name := util.NormalizeIdentifier(rawName)
+	found, ok := s.sources[name]
+	if !ok {
+		return nil, badStatement(text, "Could not find STREAM/TABLE '%s' in the Metastore", name)
+	}
+
+	var readQueries, writeQueries []any
+	for _, q := range s.sortedQueries() {
+		if q.sink == name {
+			writeQueries = append(writeQueries, map[string]any{
+				"queryString": q.statement,
+				"sinks":       []string{q.sink},
+				"id":          q.id,
+				"queryType":   "PERSISTENT",
+				"state":       "RUNNING",
+			})
+		}
+	}
+
+	return map[string]any{
+		"@type":         "sourceDescription",
+		"statementText": text,
+		"sourceDescription": map[string]any{
+			"name":                 found.name,
+			"windowType":           nil,
+			"readQueries":          append([]any{}, readQueries...),
+			"writeQueries":         append([]any{}, writeQueries...),
+			"fields":               []any{},
+			"type":                 found.sourceType,
+			"timestamp":            found.timestamp,
+			"statistics":           "",
+			"errorStats":           "",
+			"extended":             false,
+			"keyFormat":            found.keyFormat,
+			"valueFormat":          found.valueFormat,
+			"topic":                found.topic,
+			"partitions":           found.partitions,
+			"replication":          found.replication,
+			"statement":            found.statement,
+			"queryOffsetSummaries": []any{},
+			"sourceConstraints":    []any{},
+		},
+		"warnings": []any{},
+	}, nil
+}
+
+func (s *Server) drop(text string, statement *parser.Statement) (any, *statementError) {
+
+	name := util.NormalizeIdentifier(statement.Name)
+	found, ok := s.sources[name]
+	if !ok || found.sourceType != statement.ObjectType {
+		return nil, badStatement(text, "%s%s %s does not exist.",
+			statement.ObjectType[:1], strings.ToLower(statement.ObjectType[1:]), name)
+	}
+
+	// like ksqlDB, terminate the query which writes into the source
+	for id, q := range s.queries {
+		if q.sink == name {
+			delete(s.queries, id)
+		}
+	}
+
+	delete(s.sources, name)
+	return s.command(text, fmt.Sprintf("%s/`%s`/drop", strings.ToLower(found.sourceType), name), fmt.Sprintf("Source `%s` (topic: %s) was dropped.", name, found.topic), nil), nil
+}
+
+func (s *Server) terminate(text string, id string) (any, *statementError) {
+
+	if strings.EqualFold(id, "ALL") {
+		s.queries = map[string]*query{}
+	} else {
+		if _, ok := s.queries[id]; !ok {
+			return nil, badStatement(text, "Unknown queryId: %s", id)
+		}
+		delete(s.queries, id)
+	}
+
+	return s.command(text, fmt.Sprintf("terminate/%s/execute", id), "Query terminated.", nil), nil
+}
+
+func (s *Server) show(text string, what string) (any, *statementError) {
+
+	switch what {
+	case "STREAMS", "TABLES":
+		sourceType := strings.TrimSuffix(what, "S")
+		list := []any{}
+		for _, found := range s.sortedSources() {
+			if found.sourceType != sourceType {
+				continue
+			}
+			list = append(list, map[string]any{
+				"type":        found.sourceType,
+				"name":        found.name,
+				"topic":       found.topic,
+				"keyFormat":   found.keyFormat,
+				"valueFormat": found.valueFormat,
+				"isWindowed":  false,
+			})
+		}
+		return map[string]any{
+			"@type":               strings.ToLower(what),
+			"statementText":       text,
+			strings.ToLower(what): list,
+			"warnings":            []any{},
+		}, nil
+
+	case "QUERIES":
+		list := []any{}
+		for _, q := range s.sortedQueries() {
+			list = append(list, map[string]any{
+				"queryString":     q.statement,
+				"sinks":           []string{q.sink},
+				"sinkKafkaTopics": []string{s.sources[q.sink].topic},
+				"id":              q.id,
+				"queryType":       "PERSISTENT",
+				"state":           "RUNNING",
+			})
+		}
+		return map[string]any{
+			"@type":         "queries",
+			"statementText": text,
+			"queries":       list,
+			"warnings":      []any{},
+		}, nil
+
+	case "TYPES":
+		return map[string]any{
+			"@type":         "type_list",
+			"statementText": text,
+			"types":         map[string]any{},
+			"warnings":      []any{},
+		}, nil
+	}
+
+	return nil, badStatement(text, "line 1:6: Syntax Error\nUnknown statement 'SHOW %s'", what)
+}
+
+func (s *Server) sortedSources() []*source {
+	list := make([]*source, 0, len(s.sources))
+	for _, found := range s.sources {
+		list = append(list, found)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].name < list[j].name })
+	return list
+}
+
+func (s *Server) sortedQueries() []*query {
+	list := make([]*query, 0, len(s.queries))
+	for _, q := range s.queries {
+		list = append(list, q)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].id < list[j].id })
+	return list
+}
+
+func isSchemaInferenceFormat(statement *parser.Statement) bool {
+	switch strings.ToUpper(property(statement, "VALUE_FORMAT", property(statement, "FORMAT", ""))) {
+	case "AVRO", "PROTOBUF", "JSON_SR":
+		return true
+	}
+	return false
+}
+
+func property(statement *parser.Statement, key string, defaultValue string) string {
+	if value, ok := statement.Properties[key]; ok {
+		return value
+	}
+	return defaultValue
+}
+
+func int64Property(statement *parser.Statement, key string, defaultValue int64) int64 {
+	if value, err := strconv.ParseInt(statement.Properties[key], 10, 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/vnd.ksql.v1+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,96 @@
+package ksqldb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"regexp"
+	"sort"
+)
+
+// defaultLogBodyLimit is the number of bytes of request and response bodies which are logged by default.
+const defaultLogBodyLimit = 4096
+
+// maskedValue replaces sensitive values in logs, like tflog does for masked fields.
+const maskedValue = "***"
+
+// sensitivePropertyPattern matches the keys of streams properties which may contain credentials, e.g.
+// sasl.jaas.config, ssl.key.password or ksql.schema.registry.basic.auth.user.info.
+var sensitivePropertyPattern = regexp.MustCompile(`(?i)(password|secret|jaas|user\.info|token|credential|api\.key)`)
+
+// inlineCredentialPattern matches credentials within log values, e.g. the password of a JAAS configuration
+// which is part of a response body.
+var inlineCredentialPattern = regexp.MustCompile(`(?i)((?:password|secret)\\?["']?\s*[=:]\s*\\?["'])[^"'\\]*`)
+
+func isSensitiveProperty(key string) bool {
+	return sensitivePropertyPattern.MatchString(key)
+}
+
+// logRequest adds the statement and its streams properties to the log fields of ctx. Every streams property is
+// a field of its own, so that the values of sensitive properties are masked by tflog.
+func logRequest(ctx context.Context, payload *Payload) context.Context {
+
+	ctx = tflog.SetField(ctx, "ksql", payload.Ksql)
+	if payload.CommandSequenceNumber != nil {
+		ctx = tflog.SetField(ctx, "command_sequence_number", *payload.CommandSequenceNumber)
+	}
+
+	keys := make([]string, 0, len(payload.Properties))
+	for key := range payload.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sensitive []string
+	for _, key := range keys {
+		field := "streams_properties." + key
+		ctx = tflog.SetField(ctx, field, payload.Properties[key])
+		if isSensitiveProperty(key) {
+			sensitive = append(sensitive, field)
+		}
+	}
+
+	return maskCredentials(ctx, sensitive...)
+}
+
+// maskCredentials masks the values of the given fields and credentials within all other field values.
+func maskCredentials(ctx context.Context, fields ...string) context.Context {
+	if len(fields) > 0 {
+		ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, fields...)
+	}
+	return tflog.MaskAllFieldValuesRegexes(ctx, inlineCredentialPattern)
+}
+
+// redactedBody returns the JSON body of the request with the values of sensitive streams properties masked.
+func redactedBody(payload *Payload) ([]byte, error) {
+
+	redacted := *payload
+	redacted.Properties = make(map[string]string, len(payload.Properties))
+	for key, value := range payload.Properties {
+		if isSensitiveProperty(key) {
+			value = maskedValue
+		}
+		redacted.Properties[key] = value
+	}
+	if payload.Properties == nil {
+		redacted.Properties = nil
+	}
+
+	return json.Marshal(redacted)
+}
+
+// truncateBody shortens a body to the configured number of bytes for logging. A limit of 0 keeps the body.
+func (c *Client) truncateBody(body []byte) string {
+
+	limit := defaultLogBodyLimit
+	if c.logBodyLimit != nil {
+		limit = *c.logBodyLimit
+	}
+
+	if limit <= 0 || len(body) <= limit {
+		return string(body)
+	}
+
+	return fmt.Sprintf("%s... (%d more bytes)", body[:limit], len(body)-limit)
+}
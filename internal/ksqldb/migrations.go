@@ -0,0 +1,121 @@
+package ksqldb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"terraform-provider-ksqldb/internal/ksqldb/parser"
+	"terraform-provider-ksqldb/internal/ksqldb/util"
+)
+
+// migrationFilePattern matches the files of a ksql-migrations directory, e.g. V000001__create_stream_orders.sql.
+var migrationFilePattern = regexp.MustCompile(`^V(\d{6})__\w*\.sql$`)
+
+var migrationDescriptionInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// MigrationWriter writes statements to a ksql-migrations directory, one versioned file per statement.
+type MigrationWriter struct {
+	directory string
+	mu        sync.Mutex
+}
+
+// NewMigrationWriter creates a MigrationWriter for the given directory, which is created if necessary.
+func NewMigrationWriter(directory string) *MigrationWriter {
+	return &MigrationWriter{directory: directory}
+}
+
+// write writes the statement to the next version of the migrations. Streams properties are set before
+// and unset after the statement, since ksql-migrations applies all files in the same session.
+func (w *MigrationWriter) write(ksql string, properties map[string]string) (string, error) {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := os.MkdirAll(w.directory, 0o755); err != nil {
+		return "", err
+	}
+
+	version, err := w.nextVersion()
+	if err != nil {
+		return "", err
+	}
+
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteString(fmt.Sprintf("SET '%s'='%s';\n", key, strings.ReplaceAll(properties[key], "'", "''")))
+	}
+	sb.WriteString(strings.TrimSpace(ksql))
+	sb.WriteString("\n")
+	for _, key := range keys {
+		sb.WriteString(fmt.Sprintf("UNSET '%s';\n", key))
+	}
+
+	file := filepath.Join(w.directory, fmt.Sprintf("V%06d__%s.sql", version, migrationDescription(ksql)))
+
+	// never overwrite a migration, it may already have been applied
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(sb.String()); err != nil {
+		return "", err
+	}
+
+	return file, nil
+}
+
+// nextVersion returns the version following the highest version in the directory.
+func (w *MigrationWriter) nextVersion() (int, error) {
+
+	entries, err := os.ReadDir(w.directory)
+	if err != nil {
+		return 0, err
+	}
+
+	latest := 0
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		if version, _ := strconv.Atoi(match[1]); version > latest {
+			latest = version
+		}
+	}
+
+	return latest + 1, nil
+}
+
+// migrationDescription derives the description of a migration from its statement, e.g. create_stream_orders.
+func migrationDescription(ksql string) string {
+
+	description := "statement"
+
+	if statement, err := parser.Parse(ksql); err == nil {
+		parts := []string{statement.Kind}
+		if statement.ObjectType != "" {
+			parts = append(parts, statement.ObjectType)
+		}
+		if statement.Name != "" {
+			parts = append(parts, util.NormalizeIdentifier(statement.Name))
+		}
+		description = strings.Join(parts, "_")
+	}
+
+	description = migrationDescriptionInvalidChars.ReplaceAllString(strings.ToLower(description), "_")
+
+	return strings.Trim(description, "_")
+}
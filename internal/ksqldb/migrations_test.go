@@ -0,0 +1,113 @@
+package ksqldb
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMigrationWriter(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "migrations")
+	writer := NewMigrationWriter(directory)
+
+	file, err := writer.write("CREATE STREAM ORDERS WITH (KAFKA_TOPIC='orders');", map[string]string{
+		"auto.offset.reset": "earliest",
+		"ksql.description":  "it's new",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(file) != "V000001__create_stream_orders.sql" {
+		t.Errorf("unexpected file %s", file)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SET 'auto.offset.reset'='earliest';\n" +
+		"SET 'ksql.description'='it''s new';\n" +
+		"CREATE STREAM ORDERS WITH (KAFKA_TOPIC='orders');\n" +
+		"UNSET 'auto.offset.reset';\n" +
+		"UNSET 'ksql.description';\n"
+	if string(content) != expected {
+		t.Errorf("unexpected migration:\n%s", content)
+	}
+
+	// versions continue after existing migrations, other files are ignored
+	for _, name := range []string{"V000007__applied.sql", "V99__invalid.sql", "README.md"} {
+		if err := os.WriteFile(filepath.Join(directory, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file, err = writer.write("DROP STREAM ORDERS;", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(file) != "V000008__drop_stream_orders.sql" {
+		t.Errorf("unexpected file %s", file)
+	}
+	if content, _ := os.ReadFile(file); string(content) != "DROP STREAM ORDERS;\n" {
+		t.Errorf("unexpected migration:\n%s", content)
+	}
+}
+
+func TestMigrationDescription(t *testing.T) {
+
+	tests := []struct {
+		ksql string
+		want string
+	}{
+		{"CREATE OR REPLACE STREAM ORDERS WITH (KAFKA_TOPIC='orders');", "create_stream_orders"},
+		{"CREATE STREAM `Large Orders` AS SELECT * FROM ORDERS;", "create_stream_large_orders"},
+		{"DROP STREAM IF EXISTS ORDERS DELETE TOPIC;", "drop_stream_orders"},
+		{"INSERT INTO ORDERS SELECT * FROM OLD;", "insert_orders"},
+		{"TERMINATE ALL;", "terminate"},
+		{"CREATE STREAM", "statement"},
+	}
+
+	for _, test := range tests {
+		if got := migrationDescription(test.ksql); got != test.want {
+			t.Errorf("%q: expected %q, got %q", test.ksql, test.want, got)
+		}
+	}
+}
+
+func TestSetPlannedState(t *testing.T) {
+
+	replicas := int64(3)
+	defaults := StreamDefaults{ValueFormat: "AVRO", Replicas: &replicas}
+
+	data := emptyStream("ORDERS")
+	data.KafkaTopic = types.StringValue("orders")
+	data.KeyFormat = types.StringUnknown()
+	data.ValueFormat = types.StringUnknown()
+	data.Replicas = types.Int64Unknown()
+	data.Partitions = types.Int64Unknown()
+	data.Statement = types.StringUnknown()
+
+	setPlannedState(&data, defaults, types.StringValue("CREATE STREAM ORDERS;"))
+
+	want := emptyStream("ORDERS")
+	want.KafkaTopic = types.StringValue("orders")
+	want.ValueFormat = types.StringValue("AVRO")
+	want.Replicas = types.Int64Value(3)
+	want.Statement = types.StringValue("CREATE STREAM ORDERS;")
+
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("expected %+v, got %+v", want, data)
+	}
+
+	// known values are kept
+	known := want
+	known.Statement = types.StringValue("CREATE OR REPLACE STREAM ORDERS;")
+	planned := known
+	setPlannedState(&planned, StreamDefaults{}, types.StringNull())
+
+	if !reflect.DeepEqual(planned, known) {
+		t.Errorf("expected %+v, got %+v", known, planned)
+	}
+}
@@ -0,0 +1,36 @@
+package modifiers
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"terraform-provider-ksqldb/internal/ksqldb/util"
+)
+
+var equivalentIdentifierDescription = "If the name only differs in the case of an unquoted identifier, the name in the state is kept"
+
+type useStateForEquivalentIdentifier struct{}
+
+func (m useStateForEquivalentIdentifier) Description(_ context.Context) string {
+	return equivalentIdentifierDescription
+}
+
+func (m useStateForEquivalentIdentifier) MarkdownDescription(_ context.Context) string {
+	return equivalentIdentifierDescription
+}
+
+// PlanModifyString keeps the name in the state when ksqlDB resolves the configured name to the same object,
+// e.g. ORDERS after importing orders, so it doesn't require a replacement.
+func (m useStateForEquivalentIdentifier) PlanModifyString(_ context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+
+	if req.StateValue.IsNull() || req.PlanValue.IsNull() || req.PlanValue.IsUnknown() {
+		return
+	}
+
+	if util.NormalizeIdentifier(req.PlanValue.ValueString()) == util.NormalizeIdentifier(req.StateValue.ValueString()) {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+// UseStateForEquivalentIdentifier must precede stringplanmodifier.RequiresReplace, which then only
+// replaces the stream if the name refers to another object.
+var UseStateForEquivalentIdentifier planmodifier.String = useStateForEquivalentIdentifier{}
@@ -5,6 +5,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
@@ -38,3 +39,9 @@ func isSourceStreamInt64(ctx context.Context, req planmodifier.Int64Request, res
 }
 
 var RequiresReplaceIfIsSourceStreamInt64 = int64planmodifier.RequiresReplaceIf(isSourceStreamInt64, description, description)
+
+func isSourceStreamObject(ctx context.Context, req planmodifier.ObjectRequest, resp *objectplanmodifier.RequiresReplaceIfFuncResponse) {
+	isSourceStream(ctx, req.State, &resp.RequiresReplace)
+}
+
+var RequiresReplaceIfIsSourceStreamObject = objectplanmodifier.RequiresReplaceIf(isSourceStreamObject, description, description)
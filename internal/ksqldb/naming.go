@@ -0,0 +1,39 @@
+package ksqldb
+
+import (
+	"fmt"
+	"strings"
+	"terraform-provider-ksqldb/internal/ksqldb/util"
+)
+
+// NamingConvention are the prefixes and suffixes which names of objects and topics must have, e.g. to
+// namespace them per environment or team.
+type NamingConvention struct {
+	ObjectPrefix string
+	ObjectSuffix string
+	TopicPrefix  string
+	TopicSuffix  string
+}
+
+// checkObjectName checks the name of a stream or table as ksqlDB stores it, i.e. uppercase unless it is
+// enclosed by backticks.
+func (n NamingConvention) checkObjectName(name string) error {
+	return checkAffixes("name", util.NormalizeIdentifier(name), n.ObjectPrefix, n.ObjectSuffix)
+}
+
+// checkTopicName checks the name of a Kafka topic.
+func (n NamingConvention) checkTopicName(topic string) error {
+	return checkAffixes("topic name", topic, n.TopicPrefix, n.TopicSuffix)
+}
+
+func checkAffixes(kind string, name string, prefix string, suffix string) error {
+
+	if !strings.HasPrefix(name, prefix) {
+		return fmt.Errorf("the %s %s must start with '%s' according to the provider's naming configuration", kind, name, prefix)
+	}
+	if !strings.HasSuffix(name, suffix) {
+		return fmt.Errorf("the %s %s must end with '%s' according to the provider's naming configuration", kind, name, suffix)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package ksqldb
+
+import "testing"
+
+func TestNamingConvention(t *testing.T) {
+
+	naming := NamingConvention{ObjectPrefix: "DEV_", ObjectSuffix: "_V1", TopicPrefix: "payments.", TopicSuffix: ".v1"}
+
+	objects := []struct {
+		name string
+		// error is the expected error, empty if the name is valid
+		error string
+	}{
+		{"DEV_ORDERS_V1", ""},
+		{"dev_orders_v1", ""},
+		{"`DEV_Orders_V1`", ""},
+		{"`dev_orders_v1`", "the name dev_orders_v1 must start with 'DEV_' according to the provider's naming configuration"},
+		{"ORDERS_V1", "the name ORDERS_V1 must start with 'DEV_' according to the provider's naming configuration"},
+		{"DEV_ORDERS", "the name DEV_ORDERS must end with '_V1' according to the provider's naming configuration"},
+	}
+
+	for _, test := range objects {
+		err := naming.checkObjectName(test.name)
+		if test.error == "" && err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+		}
+		if test.error != "" && (err == nil || err.Error() != test.error) {
+			t.Errorf("%s: expected error %q, got %v", test.name, test.error, err)
+		}
+	}
+
+	topics := []struct {
+		topic string
+		error string
+	}{
+		{"payments.orders.v1", ""},
+		{"Payments.orders.v1", "the topic name Payments.orders.v1 must start with 'payments.' according to the provider's naming configuration"},
+		{"payments.orders", "the topic name payments.orders must end with '.v1' according to the provider's naming configuration"},
+	}
+
+	for _, test := range topics {
+		err := naming.checkTopicName(test.topic)
+		if test.error == "" && err != nil {
+			t.Errorf("%s: unexpected error: %s", test.topic, err)
+		}
+		if test.error != "" && (err == nil || err.Error() != test.error) {
+			t.Errorf("%s: expected error %q, got %v", test.topic, test.error, err)
+		}
+	}
+
+	// without a naming convention, every name is valid
+	if err := (NamingConvention{}).checkObjectName("orders"); err != nil {
+		t.Errorf("unexpected error without a naming convention: %s", err)
+	}
+}
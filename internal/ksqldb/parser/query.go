@@ -0,0 +1,181 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Query is a SELECT query split into its clauses. The clauses are kept as written, without their keywords.
+type Query struct {
+	// Projection holds the items of the SELECT list.
+	Projection []string
+	// From holds the FROM clause including all joins.
+	From        string
+	Window      string
+	Where       string
+	GroupBy     string
+	PartitionBy string
+	Having      string
+	Emit        string
+	Limit       string
+}
+
+// queryClauses are the top level keywords which start a new clause of a query.
+var queryClauses = [][]string{
+	{"SELECT"}, {"FROM"}, {"WINDOW"}, {"WHERE"}, {"GROUP", "BY"}, {"PARTITION", "BY"}, {"HAVING"}, {"EMIT"}, {"LIMIT"},
+}
+
+// ParseQuery splits a SELECT query into its clauses.
+func ParseQuery(text string) (*Query, error) {
+
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), ";"))
+
+	tokens, err := Tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 || !tokens[0].Is("SELECT") {
+		return nil, fmt.Errorf("query must start with SELECT: %s", text)
+	}
+
+	clauses := map[string]string{}
+	var projection []Token
+
+	current := ""
+	start := 0
+	depth := 0
+
+	flush := func(end int) {
+		if current == "" {
+			return
+		}
+		clause := ""
+		if start < end {
+			clause = strings.TrimSpace(text[tokens[start].Start:tokens[end-1].End])
+		}
+		clauses[current] = clause
+		if current == "SELECT" {
+			projection = tokens[start:end]
+		}
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+
+		if token.Type == Symbol {
+			switch token.Text {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			}
+			continue
+		}
+
+		if depth != 0 || token.Type != Word {
+			continue
+		}
+
+		for _, keywords := range queryClauses {
+			if !matchesKeywords(tokens[i:], keywords) {
+				continue
+			}
+			flush(i)
+			current = strings.Join(keywords, " ")
+			i += len(keywords) - 1
+			start = i + 1
+			break
+		}
+	}
+	flush(len(tokens))
+
+	return &Query{
+		Projection:  splitProjection(text, projection),
+		From:        clauses["FROM"],
+		Window:      clauses["WINDOW"],
+		Where:       clauses["WHERE"],
+		GroupBy:     clauses["GROUP BY"],
+		PartitionBy: clauses["PARTITION BY"],
+		Having:      clauses["HAVING"],
+		Emit:        clauses["EMIT"],
+		Limit:       clauses["LIMIT"],
+	}, nil
+}
+
+func matchesKeywords(tokens []Token, keywords []string) bool {
+	if len(tokens) < len(keywords) {
+		return false
+	}
+	for i, keyword := range keywords {
+		if !tokens[i].Is(keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitProjection splits the SELECT list at top level commas.
+func splitProjection(text string, tokens []Token) []string {
+
+	var items []string
+	depth := 0
+	start := 0
+
+	for i, token := range tokens {
+		if token.Type != Symbol {
+			continue
+		}
+		switch token.Text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",":
+			if depth == 0 && start < i {
+				items = append(items, text[tokens[start].Start:tokens[i-1].End])
+				start = i + 1
+			}
+		}
+	}
+
+	if start < len(tokens) {
+		items = append(items, text[tokens[start].Start:tokens[len(tokens)-1].End])
+	}
+
+	return items
+}
+
+// ColumnName returns the name of the column a projection item results in, as ksqlDB stores it. An empty
+// string is returned if the name is generated by ksqlDB, e.g. for expressions without alias, or for *.
+func ColumnName(item string) string {
+
+	tokens, err := Tokenize(item)
+	if err != nil || len(tokens) == 0 {
+		return ""
+	}
+
+	last := tokens[len(tokens)-1]
+	if last.Type != Word && last.Type != QuotedIdentifier {
+		return ""
+	}
+
+	name := strings.ToUpper(last.Text)
+	if last.Type == QuotedIdentifier {
+		name = last.Value
+	}
+
+	switch {
+	// expression AS alias
+	case len(tokens) >= 3 && tokens[len(tokens)-2].Is("AS"):
+		return name
+	// column
+	case len(tokens) == 1:
+		return name
+	// source.column
+	case len(tokens) == 3 && tokens[1].Text == ".":
+		return name
+	}
+
+	return ""
+}
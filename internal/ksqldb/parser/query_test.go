@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+
+	tests := []struct {
+		text string
+		want Query
+	}{
+		{
+			text: "SELECT ID, FN(A, B) AS X, `Col` FROM S WHERE A > 1 EMIT CHANGES;",
+			want: Query{Projection: []string{"ID", "FN(A, B) AS X", "`Col`"}, From: "S", Where: "A > 1", Emit: "CHANGES"},
+		},
+		{
+			text: "select s.id, count(*) from s join t within 1 hour on s.id = t.id window tumbling (size 1 hour) " +
+				"group by s.id having count(*) > 1 emit final",
+			want: Query{Projection: []string{"s.id", "count(*)"}, From: "s join t within 1 hour on s.id = t.id",
+				Window: "tumbling (size 1 hour)", GroupBy: "s.id", Having: "count(*) > 1", Emit: "final"},
+		},
+		{
+			text: "SELECT * FROM S WHERE A IN (SELECT B FROM T) PARTITION BY A LIMIT 10",
+			want: Query{Projection: []string{"*"}, From: "S", Where: "A IN (SELECT B FROM T)", PartitionBy: "A", Limit: "10"},
+		},
+		{
+			text: "-- comment\nSELECT 'FROM' FROM S",
+			want: Query{Projection: []string{"'FROM'"}, From: "S"},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseQuery(test.text)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", test.text, err)
+			continue
+		}
+		if !reflect.DeepEqual(*got, test.want) {
+			t.Errorf("%q: expected %+v, got %+v", test.text, test.want, *got)
+		}
+	}
+
+	for _, text := range []string{"", "INSERT INTO S SELECT * FROM T", "SELECT 'unterminated"} {
+		if _, err := ParseQuery(text); err == nil {
+			t.Errorf("%q: expected an error", text)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+
+	tests := []struct {
+		item string
+		want string
+	}{
+		{"id", "ID"},
+		{"`Id`", "Id"},
+		{"s.id", "ID"},
+		{"FN(a) AS total", "TOTAL"},
+		{"FN(a)", ""},
+		{"*", ""},
+	}
+
+	for _, test := range tests {
+		if got := ColumnName(test.item); got != test.want {
+			t.Errorf("%q: expected %q, got %q", test.item, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Statement is the parsed form of a single KSQL statement. Only the parts which are relevant for the provider
+// are parsed, everything else is kept as written.
+type Statement struct {
+	// Kind is the statement type in uppercase, e.g. CREATE, DROP, INSERT, SET or UNSET.
+	Kind string
+	// ObjectType is the type of the object the statement refers to in uppercase, e.g. STREAM, TABLE or TYPE.
+	ObjectType string
+	OrReplace  bool
+	Source     bool
+	// Name is the name of the object as written in the statement, including backticks.
+	Name string
+	// Columns is the column definition list as written in the statement, without the enclosing parentheses.
+	Columns string
+	// Properties holds the WITH properties of the statement with uppercase keys. String values are kept as
+	// written between the quotes.
+	// For SET and UNSET statements it holds the property which is set or unset.
+	Properties map[string]string
+	// Query is the query as written in the statement, e.g. the SELECT of a CREATE ... AS SELECT statement.
+	Query string
+	// Text is the full statement as written, without the terminating semicolon.
+	Text string
+}
+
+// Parse parses a single KSQL statement.
+func Parse(text string) (*Statement, error) {
+
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), ";"))
+
+	tokens, err := Tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty statement")
+	}
+
+	p := &statementParser{text: text, tokens: tokens}
+	statement := &Statement{
+		Kind:       strings.ToUpper(tokens[0].Text),
+		Properties: map[string]string{},
+		Text:       text,
+	}
+
+	switch statement.Kind {
+	case "CREATE":
+		err = p.parseCreate(statement)
+	case "DROP":
+		err = p.parseDrop(statement)
+	case "INSERT":
+		err = p.parseInsert(statement)
+	case "SET", "UNSET":
+		err = p.parseSet(statement)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return statement, nil
+}
+
+type statementParser struct {
+	text   string
+	tokens []Token
+	pos    int
+}
+
+func (p *statementParser) peek() (Token, bool) {
+	if p.pos >= len(p.tokens) {
+		return Token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// accept consumes the next tokens if they are the given keywords.
+func (p *statementParser) accept(keywords ...string) bool {
+	if p.pos+len(keywords) > len(p.tokens) {
+		return false
+	}
+	for i, keyword := range keywords {
+		if !p.tokens[p.pos+i].Is(keyword) {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+	return true
+}
+
+func (p *statementParser) acceptSymbol(symbol string) bool {
+	token, ok := p.peek()
+	if !ok || token.Type != Symbol || token.Text != symbol {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+func (p *statementParser) name() (string, error) {
+	token, ok := p.peek()
+	if !ok || (token.Type != Word && token.Type != QuotedIdentifier) {
+		return "", fmt.Errorf("expected a name in statement: %s", p.text)
+	}
+	p.pos++
+	return token.Text, nil
+}
+
+// parenthesized consumes a parenthesized block and returns its content as written.
+func (p *statementParser) parenthesized() (string, []Token, error) {
+
+	open, _ := p.peek()
+	if !p.acceptSymbol("(") {
+		return "", nil, fmt.Errorf("expected '(' in statement: %s", p.text)
+	}
+
+	start := p.pos
+	depth := 1
+
+	for ; p.pos < len(p.tokens); p.pos++ {
+		token := p.tokens[p.pos]
+		if token.Type != Symbol {
+			continue
+		}
+		switch token.Text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+		if depth == 0 {
+			content := p.text[open.End:token.Start]
+			inner := p.tokens[start:p.pos]
+			p.pos++
+			return strings.TrimSpace(content), inner, nil
+		}
+	}
+
+	return "", nil, fmt.Errorf("unbalanced parentheses in statement: %s", p.text)
+}
+
+// properties parses the content of a WITH clause.
+func (p *statementParser) properties(tokens []Token, properties map[string]string) error {
+
+	for i := 0; i < len(tokens); {
+		if i+2 >= len(tokens) || tokens[i+1].Text != "=" {
+			return fmt.Errorf("invalid WITH clause in statement: %s", p.text)
+		}
+
+		key := strings.ToUpper(tokens[i].Value)
+		value := tokens[i+2].Value
+
+		switch {
+		// string values are kept as written, the provider doesn't escape them either
+		case tokens[i+2].Type == String:
+			value = tokens[i+2].Text[1 : len(tokens[i+2].Text)-1]
+		// negative numbers, e.g. RETENTION_MS=-1 for infinite retention
+		case tokens[i+2].Type == Symbol && tokens[i+2].Text == "-" && i+3 < len(tokens) && tokens[i+3].Type == Number:
+			value = "-" + tokens[i+3].Value
+			i++
+		case tokens[i+2].Type == Symbol:
+			return fmt.Errorf("invalid WITH clause in statement: %s", p.text)
+		}
+
+		properties[key] = value
+		i += 3
+
+		if i < len(tokens) {
+			if tokens[i].Text != "," {
+				return fmt.Errorf("invalid WITH clause in statement: %s", p.text)
+			}
+			i++
+		}
+	}
+
+	return nil
+}
+
+func (p *statementParser) with(statement *Statement) error {
+	if !p.accept("WITH") {
+		return nil
+	}
+	_, tokens, err := p.parenthesized()
+	if err != nil {
+		return err
+	}
+	return p.properties(tokens, statement.Properties)
+}
+
+// rest returns the remaining statement as written.
+func (p *statementParser) rest() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return strings.TrimSpace(p.text[p.tokens[p.pos].Start:])
+}
+
+func (p *statementParser) parseCreate(statement *Statement) error {
+
+	p.pos = 1
+	statement.OrReplace = p.accept("OR", "REPLACE")
+	statement.Source = p.accept("SOURCE")
+
+	token, ok := p.peek()
+	if !ok || token.Type != Word {
+		return fmt.Errorf("expected object type in statement: %s", p.text)
+	}
+	statement.ObjectType = strings.ToUpper(token.Text)
+	p.pos++
+
+	p.accept("IF", "NOT", "EXISTS")
+
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	statement.Name = name
+
+	if statement.ObjectType == "TYPE" {
+		if p.accept("AS") {
+			statement.Query = p.rest()
+		}
+		return nil
+	}
+
+	if token, ok := p.peek(); ok && token.Type == Symbol && token.Text == "(" {
+		statement.Columns, _, err = p.parenthesized()
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := p.with(statement); err != nil {
+		return err
+	}
+
+	if p.accept("AS") {
+		statement.Query = p.rest()
+	}
+
+	return nil
+}
+
+func (p *statementParser) parseDrop(statement *Statement) error {
+
+	p.pos = 1
+
+	token, ok := p.peek()
+	if !ok || token.Type != Word {
+		return fmt.Errorf("expected object type in statement: %s", p.text)
+	}
+	statement.ObjectType = strings.ToUpper(token.Text)
+	p.pos++
+
+	p.accept("IF", "EXISTS")
+
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	statement.Name = name
+
+	return nil
+}
+
+func (p *statementParser) parseInsert(statement *Statement) error {
+
+	p.pos = 1
+
+	if !p.accept("INTO") {
+		return fmt.Errorf("expected INTO in statement: %s", p.text)
+	}
+
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	statement.Name = name
+
+	if token, ok := p.peek(); ok && token.Type == Symbol && token.Text == "(" {
+		statement.Columns, _, err = p.parenthesized()
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := p.with(statement); err != nil {
+		return err
+	}
+
+	statement.Query = p.rest()
+
+	return nil
+}
+
+func (p *statementParser) parseSet(statement *Statement) error {
+
+	tokens := p.tokens[1:]
+
+	if len(tokens) == 0 || tokens[0].Type != String {
+		return fmt.Errorf("expected a property name in statement: %s", p.text)
+	}
+
+	key := tokens[0].Value
+
+	if statement.Kind == "UNSET" {
+		statement.Properties[key] = ""
+		return nil
+	}
+
+	if len(tokens) != 3 || tokens[1].Text != "=" {
+		return fmt.Errorf("expected 'property' = 'value' in statement: %s", p.text)
+	}
+
+	statement.Properties[key] = tokens[2].Value
+
+	return nil
+}
+
+// EquivalentQueries reports whether both queries only differ in whitespace, comments, the case of keywords
+// and unquoted identifiers, or a trailing EMIT CHANGES which ksqlDB adds to persistent queries.
+func EquivalentQueries(a, b string) bool {
+
+	tokensA, errA := Tokenize(a)
+	tokensB, errB := Tokenize(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+
+	tokensA = trimEmitChanges(tokensA)
+	tokensB = trimEmitChanges(tokensB)
+
+	if len(tokensA) != len(tokensB) {
+		return false
+	}
+
+	for i := range tokensA {
+		if tokensA[i].Type != tokensB[i].Type {
+			return false
+		}
+		if tokensA[i].Type == Word {
+			if !strings.EqualFold(tokensA[i].Text, tokensB[i].Text) {
+				return false
+			}
+		} else if tokensA[i].Value != tokensB[i].Value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func trimEmitChanges(tokens []Token) []Token {
+	if n := len(tokens); n > 0 && tokens[n-1].Type == Symbol && tokens[n-1].Text == ";" {
+		tokens = tokens[:n-1]
+	}
+	if n := len(tokens); n >= 2 && tokens[n-2].Is("EMIT") && tokens[n-1].Is("CHANGES") {
+		tokens = tokens[:n-2]
+	}
+	return tokens
+}
@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+
+	tests := []struct {
+		name string
+		text string
+		want Statement
+	}{
+		{
+			name: "create or replace",
+			text: "CREATE OR REPLACE STREAM ORDERS WITH (KAFKA_TOPIC='orders', PARTITIONS=1, VALUE_FORMAT='JSON');",
+			want: Statement{Kind: "CREATE", ObjectType: "STREAM", OrReplace: true, Name: "ORDERS",
+				Properties: map[string]string{"KAFKA_TOPIC": "orders", "PARTITIONS": "1", "VALUE_FORMAT": "JSON"}},
+		},
+		{
+			name: "source",
+			text: "create source stream `Orders` with (kafka_topic='orders')",
+			want: Statement{Kind: "CREATE", ObjectType: "STREAM", Source: true, Name: "`Orders`",
+				Properties: map[string]string{"KAFKA_TOPIC": "orders"}},
+		},
+		{
+			name: "nested parentheses in column types",
+			text: "CREATE STREAM S (ID STRING KEY, TAGS MAP<STRING, ARRAY<STRING>>, AMOUNT DECIMAL(10, 2), ADDR STRUCT<`Street` STRING>) WITH (KAFKA_TOPIC='s')",
+			want: Statement{Kind: "CREATE", ObjectType: "STREAM", Name: "S",
+				Columns:    "ID STRING KEY, TAGS MAP<STRING, ARRAY<STRING>>, AMOUNT DECIMAL(10, 2), ADDR STRUCT<`Street` STRING>",
+				Properties: map[string]string{"KAFKA_TOPIC": "s"}},
+		},
+		{
+			name: "quoted values are kept as written",
+			text: "CREATE STREAM S WITH (KAFKA_TOPIC='s', TIMESTAMP='`ts`', TIMESTAMP_FORMAT='yyyy-MM-dd''T''HH:mm:ss')",
+			want: Statement{Kind: "CREATE", ObjectType: "STREAM", Name: "S",
+				Properties: map[string]string{"KAFKA_TOPIC": "s", "TIMESTAMP": "`ts`", "TIMESTAMP_FORMAT": "yyyy-MM-dd''T''HH:mm:ss"}},
+		},
+		{
+			name: "comments",
+			text: "-- orders\nCREATE STREAM S /* topic */ WITH (KAFKA_TOPIC='s') AS SELECT * FROM T",
+			want: Statement{Kind: "CREATE", ObjectType: "STREAM", Name: "S",
+				Properties: map[string]string{"KAFKA_TOPIC": "s"}, Query: "SELECT * FROM T"},
+		},
+		{
+			name: "query",
+			text: "CREATE STREAM S WITH (KAFKA_TOPIC='s') AS SELECT ID, FN(A, (B)) AS X FROM T WHERE C = ';' EMIT CHANGES;",
+			want: Statement{Kind: "CREATE", ObjectType: "STREAM", Name: "S",
+				Properties: map[string]string{"KAFKA_TOPIC": "s"}, Query: "SELECT ID, FN(A, (B)) AS X FROM T WHERE C = ';' EMIT CHANGES"},
+		},
+		{
+			name: "negative numbers and exponents",
+			text: "CREATE STREAM X WITH (KAFKA_TOPIC='x', RETENTION_MS=-1, REPLICAS=1e2, VALUE_FORMAT='JSON')",
+			want: Statement{Kind: "CREATE", ObjectType: "STREAM", Name: "X",
+				Properties: map[string]string{"KAFKA_TOPIC": "x", "RETENTION_MS": "-1", "REPLICAS": "1e2", "VALUE_FORMAT": "JSON"}},
+		},
+		{
+			name: "drop",
+			text: "DROP STREAM IF EXISTS ORDERS DELETE TOPIC;",
+			want: Statement{Kind: "DROP", ObjectType: "STREAM", Name: "ORDERS", Properties: map[string]string{}},
+		},
+		{
+			name: "insert",
+			text: "INSERT INTO ORDERS SELECT * FROM OLD",
+			want: Statement{Kind: "INSERT", Name: "ORDERS", Properties: map[string]string{}, Query: "SELECT * FROM OLD"},
+		},
+		{
+			name: "set",
+			text: "SET 'auto.offset.reset' = 'earliest';",
+			want: Statement{Kind: "SET", Properties: map[string]string{"auto.offset.reset": "earliest"}},
+		},
+		{
+			name: "unset",
+			text: "UNSET 'auto.offset.reset';",
+			want: Statement{Kind: "UNSET", Properties: map[string]string{"auto.offset.reset": ""}},
+		},
+		{
+			name: "type",
+			text: "CREATE TYPE ADDRESS AS STRUCT<STREET STRING>",
+			want: Statement{Kind: "CREATE", ObjectType: "TYPE", Name: "ADDRESS", Properties: map[string]string{}, Query: "STRUCT<STREET STRING>"},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := Parse(test.text)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+			continue
+		}
+		// the text is only compared where it matters
+		got.Text = ""
+		if !reflect.DeepEqual(*got, test.want) {
+			t.Errorf("%s: expected %+v, got %+v", test.name, test.want, *got)
+		}
+	}
+
+	for _, text := range []string{
+		"",
+		"CREATE STREAM",
+		"CREATE STREAM S WITH (KAFKA_TOPIC='s'",
+		"CREATE STREAM S WITH (KAFKA_TOPIC)",
+		"CREATE STREAM S WITH (KAFKA_TOPIC='s' PARTITIONS=1)",
+		"CREATE STREAM S WITH (RETENTION_MS=-)",
+		"INSERT ORDERS SELECT * FROM OLD",
+		"SET auto.offset.reset",
+	} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("%q: expected an error", text)
+		}
+	}
+}
+
+func TestEquivalentQueries(t *testing.T) {
+
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"SELECT * FROM S", "select *\n  from s EMIT CHANGES;", true},
+		{"SELECT * FROM S -- all", "SELECT * /* all */ FROM S", true},
+		{"SELECT * FROM `s`", "SELECT * FROM `S`", false},
+		{"SELECT 'a' FROM S", "SELECT 'A' FROM S", false},
+		{"SELECT * FROM S WHERE A > 1", "SELECT * FROM S", false},
+	}
+
+	for _, test := range tests {
+		if got := EquivalentQueries(test.a, test.b); got != test.want {
+			t.Errorf("%q, %q: expected %t, got %t", test.a, test.b, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TokenType is the lexical category of a Token.
+type TokenType int
+
+const (
+	// Word is an unquoted keyword or identifier.
+	Word TokenType = iota
+	// QuotedIdentifier is an identifier enclosed by backticks.
+	QuotedIdentifier
+	// String is a string literal enclosed by single quotes.
+	String
+	// Number is a numeric literal.
+	Number
+	// Symbol is any other character, e.g. a parenthesis, comma or operator.
+	Symbol
+)
+
+// Token is a lexical unit of a KSQL statement.
+type Token struct {
+	Type TokenType
+	// Text is the token as written in the statement.
+	Text string
+	// Value is the unescaped content of strings and quoted identifiers and the text of all other tokens.
+	Value string
+	// Start and End are the byte offsets of the token in the statement.
+	Start int
+	End   int
+}
+
+// Is reports whether the token is the given keyword, ignoring case.
+func (t Token) Is(keyword string) bool {
+	return t.Type == Word && strings.EqualFold(t.Text, keyword)
+}
+
+// Tokenize splits a KSQL text into tokens, skipping whitespace and comments.
+func Tokenize(text string) ([]Token, error) {
+
+	var tokens []Token
+
+	for i := 0; i < len(text); {
+		c := text[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case strings.HasPrefix(text[i:], "--"):
+			end := strings.IndexByte(text[i:], '\n')
+			if end < 0 {
+				i = len(text)
+			} else {
+				i += end + 1
+			}
+
+		case strings.HasPrefix(text[i:], "/*"):
+			end := strings.Index(text[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment at position %d", i)
+			}
+			i += end + 4
+
+		case c == '\'' || c == '`':
+			end, value, err := scanQuoted(text, i, c)
+			if err != nil {
+				return nil, err
+			}
+			tokenType := String
+			if c == '`' {
+				tokenType = QuotedIdentifier
+			}
+			tokens = append(tokens, Token{Type: tokenType, Text: text[i:end], Value: value, Start: i, End: end})
+			i = end
+
+		case isDigit(c):
+			end := i
+			for end < len(text) && (isDigit(text[end]) || text[end] == '.') {
+				end++
+			}
+			// exponent, e.g. 1e2 or 1.5E-3
+			if end < len(text) && (text[end] == 'e' || text[end] == 'E') {
+				exponent := end + 1
+				if exponent < len(text) && (text[exponent] == '+' || text[exponent] == '-') {
+					exponent++
+				}
+				if exponent < len(text) && isDigit(text[exponent]) {
+					end = exponent
+					for end < len(text) && isDigit(text[end]) {
+						end++
+					}
+				}
+			}
+			tokens = append(tokens, Token{Type: Number, Text: text[i:end], Value: text[i:end], Start: i, End: end})
+			i = end
+
+		case isWordChar(c):
+			end := i
+			for end < len(text) && (isWordChar(text[end]) || isDigit(text[end])) {
+				end++
+			}
+			tokens = append(tokens, Token{Type: Word, Text: text[i:end], Value: text[i:end], Start: i, End: end})
+			i = end
+
+		default:
+			tokens = append(tokens, Token{Type: Symbol, Text: text[i : i+1], Value: text[i : i+1], Start: i, End: i + 1})
+			i++
+		}
+	}
+
+	return tokens, nil
+}
+
+// scanQuoted scans a string or quoted identifier starting at position start. The quote character is
+// escaped by doubling it.
+func scanQuoted(text string, start int, quote byte) (int, string, error) {
+
+	var sb strings.Builder
+
+	for i := start + 1; i < len(text); i++ {
+		if text[i] != quote {
+			sb.WriteByte(text[i])
+			continue
+		}
+		if i+1 < len(text) && text[i+1] == quote {
+			sb.WriteByte(quote)
+			i++
+			continue
+		}
+		return i + 1, sb.String(), nil
+	}
+
+	return 0, "", fmt.Errorf("unterminated %c at position %d", quote, start)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c == '@' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// SplitStatements splits a KSQL text into its statements at semicolons which are not part of a string,
+// quoted identifier or comment. The returned statements don't include the terminating semicolon.
+func SplitStatements(text string) ([]string, error) {
+
+	tokens, err := Tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []string
+	start := -1
+
+	for _, token := range tokens {
+		if token.Type == Symbol && token.Text == ";" {
+			if start >= 0 {
+				statements = append(statements, strings.TrimSpace(text[start:token.Start]))
+			}
+			start = -1
+			continue
+		}
+		if start < 0 {
+			start = token.Start
+		}
+	}
+
+	if start >= 0 {
+		statements = append(statements, strings.TrimSpace(text[start:]))
+	}
+
+	return statements, nil
+}
@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+
+	type token struct {
+		Type  TokenType
+		Value string
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []token
+	}{
+		{"words and symbols", "SELECT a, b FROM s", []token{
+			{Word, "SELECT"}, {Word, "a"}, {Symbol, ","}, {Word, "b"}, {Word, "FROM"}, {Word, "s"},
+		}},
+		{"escaped quote in string", "'it''s'", []token{{String, "it's"}}},
+		{"semicolon in string", "'a;b'", []token{{String, "a;b"}}},
+		{"escaped backtick in identifier", "`my``name`", []token{{QuotedIdentifier, "my`name"}}},
+		{"line comment", "a -- comment; 'not a string'\nb", []token{{Word, "a"}, {Word, "b"}}},
+		{"block comment", "a /* comment;\n */ b", []token{{Word, "a"}, {Word, "b"}}},
+		{"numbers", "1 2.5", []token{{Number, "1"}, {Number, "2.5"}}},
+		{"exponents", "1e2 1.5E-3 2e+1", []token{{Number, "1e2"}, {Number, "1.5E-3"}, {Number, "2e+1"}}},
+		{"number followed by a word", "1 EXISTS", []token{{Number, "1"}, {Word, "EXISTS"}}},
+		{"word with digits", "col_1", []token{{Word, "col_1"}}},
+	}
+
+	for _, test := range tests {
+		tokens, err := Tokenize(test.text)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+			continue
+		}
+		var got []token
+		for _, tok := range tokens {
+			got = append(got, token{tok.Type, tok.Value})
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+	}
+
+	for _, text := range []string{"'unterminated", "`unterminated", "/* unterminated"} {
+		if _, err := Tokenize(text); err == nil {
+			t.Errorf("%s: expected an error", text)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"SET 'a'='b';\nCREATE STREAM S WITH (KAFKA_TOPIC='s');", []string{"SET 'a'='b'", "CREATE STREAM S WITH (KAFKA_TOPIC='s')"}},
+		{"SELECT ';' FROM S; -- trailing; comment\n", []string{"SELECT ';' FROM S"}},
+		{"DESCRIBE S", []string{"DESCRIBE S"}},
+		{";;", nil},
+	}
+
+	for _, test := range tests {
+		got, err := SplitStatements(test.text)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", test.text, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: expected %q, got %q", test.text, test.want, got)
+		}
+	}
+}
@@ -2,10 +2,19 @@ package ksqldb
 
 import (
 	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"net/url"
 	"os"
+	"terraform-provider-ksqldb/internal/ksqldb/customvalidator"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -37,6 +46,42 @@ type KsqldbProviderModel struct {
 	// version is set to the provider version on release, "dev" when the
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
+	Url            types.String         `tfsdk:"url"`
+	Urls           types.List           `tfsdk:"urls"`
+	Username       types.String         `tfsdk:"username"`
+	Password       types.String         `tfsdk:"password"`
+	SchemaRegistry *SchemaRegistryModel `tfsdk:"schema_registry"`
+
+	DefaultKeyFormat         types.String     `tfsdk:"default_key_format"`
+	DefaultValueFormat       types.String     `tfsdk:"default_value_format"`
+	DefaultReplicas          types.Int64      `tfsdk:"default_replicas"`
+	DefaultStreamsProperties types.Map        `tfsdk:"default_streams_properties"`
+	Naming                   *NamingModel     `tfsdk:"naming"`
+	ReadOnly                 types.Bool       `tfsdk:"read_only"`
+	Migrations               *MigrationsModel `tfsdk:"migrations"`
+	LogBodyLimit             types.Int64      `tfsdk:"log_body_limit"`
+
+	RequestTimeout        types.Int64  `tfsdk:"request_timeout"`
+	ProxyUrl              types.String `tfsdk:"proxy_url"`
+	Headers               types.Map    `tfsdk:"headers"`
+	UserAgent             types.String `tfsdk:"user_agent"`
+	MaxIdleConnections    types.Int64  `tfsdk:"max_idle_connections"`
+	MaxConnectionsPerHost types.Int64  `tfsdk:"max_connections_per_host"`
+}
+
+type MigrationsModel struct {
+	Directory types.String `tfsdk:"directory"`
+	Execute   types.Bool   `tfsdk:"execute"`
+}
+
+type NamingModel struct {
+	ObjectPrefix types.String `tfsdk:"object_prefix"`
+	ObjectSuffix types.String `tfsdk:"object_suffix"`
+	TopicPrefix  types.String `tfsdk:"topic_prefix"`
+	TopicSuffix  types.String `tfsdk:"topic_suffix"`
+}
+
+type SchemaRegistryModel struct {
 	Url      types.String `tfsdk:"url"`
 	Username types.String `tfsdk:"username"`
 	Password types.String `tfsdk:"password"`
@@ -55,6 +100,16 @@ func (p *KsqldbProvider) Schema(_ context.Context, _ provider.SchemaRequest, res
 			"url": schema.StringAttribute{
 				Optional: true,
 			},
+			"urls": schema.ListAttribute{
+				MarkdownDescription: "URLs of the nodes of a ksqlDB cluster, as an alternative to `url`. Requests are sent to the first healthy node and fail over to the next one if a node can't be reached. " +
+					"Statements which may already have reached a node are not sent again. Optionally use a comma separated list in the KSQLDB_URL environment variable.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ConflictsWith(path.MatchRoot("url")),
+				},
+			},
 			"username": schema.StringAttribute{
 				Optional:  true,
 				Sensitive: true,
@@ -63,26 +118,172 @@ func (p *KsqldbProvider) Schema(_ context.Context, _ provider.SchemaRequest, res
 				Optional:  true,
 				Sensitive: true,
 			},
+			"schema_registry": schema.SingleNestedAttribute{
+				MarkdownDescription: "Optional connection to Schema Registry, required by the `ksqldb_schema` data source.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"url": schema.StringAttribute{
+						MarkdownDescription: "URL of Schema Registry. Optionally use the KSQLDB_SCHEMA_REGISTRY_URL environment variable.",
+						Optional:            true,
+					},
+					"username": schema.StringAttribute{
+						MarkdownDescription: "Optionally use the KSQLDB_SCHEMA_REGISTRY_USERNAME environment variable.",
+						Optional:            true,
+						Sensitive:           true,
+					},
+					"password": schema.StringAttribute{
+						MarkdownDescription: "Optionally use the KSQLDB_SCHEMA_REGISTRY_PASSWORD environment variable.",
+						Optional:            true,
+						Sensitive:           true,
+					},
+				},
+			},
+			"default_key_format": schema.StringAttribute{
+				MarkdownDescription: "The key format of streams which don't set `key_format`. Only applies when a stream is created or replaced.",
+				Optional:            true,
+				Validators: []validator.String{
+					customvalidator.Format(),
+				},
+			},
+			"default_value_format": schema.StringAttribute{
+				MarkdownDescription: "The value format of streams which don't set `value_format`. Only applies when a stream is created or replaced.",
+				Optional:            true,
+				Validators: []validator.String{
+					customvalidator.Format(),
+				},
+			},
+			"default_replicas": schema.Int64Attribute{
+				MarkdownDescription: "The number of replicas of the backing topic of streams which don't set `replicas`. Only applies when a stream is created or replaced.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"default_streams_properties": schema.MapAttribute{
+				MarkdownDescription: "Streams properties sent with the statements of every stream, e.g. `auto.offset.reset`. The `properties` of a stream take precedence.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"read_only": schema.BoolAttribute{
+				MarkdownDescription: "Only read from ksqlDB, e.g. to plan with credentials which may only describe objects. Plans which would change anything fail and list the statements which would be executed. Optionally use the KSQLDB_READ_ONLY environment variable.",
+				Optional:            true,
+			},
+			"request_timeout": schema.Int64Attribute{
+				MarkdownDescription: "The timeout of a single request to ksqlDB or the Schema Registry in seconds, e.g. for large `CREATE ... AS SELECT` statements on busy clusters. Defaults to `10`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the proxy for requests to ksqlDB and the Schema Registry, e.g. `http://proxy.example.com:3128`. Defaults to the proxy of the `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY` environment variables.",
+				Optional:            true,
+			},
+			"headers": schema.MapAttribute{
+				MarkdownDescription: "Additional headers of every request to ksqlDB, e.g. for an authenticating proxy. An `Authorization` header replaces the basic authentication of `username` and `password`.",
+				Optional:            true,
+				Sensitive:           true,
+				ElementType:         types.StringType,
+			},
+			"user_agent": schema.StringAttribute{
+				MarkdownDescription: "Prepended to the user agent of requests, which is `terraform-provider-ksqldb/<version>`, e.g. to identify the team or pipeline.",
+				Optional:            true,
+			},
+			"max_idle_connections": schema.Int64Attribute{
+				MarkdownDescription: "The maximum number of idle connections kept open per node. Defaults to `2`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_connections_per_host": schema.Int64Attribute{
+				MarkdownDescription: "The maximum number of connections per node, including those in use. Defaults to no limit.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"log_body_limit": schema.Int64Attribute{
+				MarkdownDescription: "The number of bytes of request and response bodies which are logged at debug and trace level, e.g. to keep large `SHOW` outputs from flooding the log. Defaults to `4096`, `0` logs complete bodies. Streams properties which may contain credentials are always masked.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"naming": schema.SingleNestedAttribute{
+				MarkdownDescription: "Naming convention for new streams and their topics, e.g. to namespace them per environment. Names which don't follow it are rejected during planning.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"object_prefix": schema.StringAttribute{
+						MarkdownDescription: "The prefix of the names of streams, e.g. `DEV_`. Names are compared as ksqlDB stores them, i.e. uppercase unless they are enclosed by backticks.",
+						Optional:            true,
+					},
+					"object_suffix": schema.StringAttribute{
+						MarkdownDescription: "The suffix of the names of streams, compared like `object_prefix`.",
+						Optional:            true,
+					},
+					"topic_prefix": schema.StringAttribute{
+						MarkdownDescription: "The prefix of the names of the backing topics, e.g. `payments.`.",
+						Optional:            true,
+					},
+					"topic_suffix": schema.StringAttribute{
+						MarkdownDescription: "The suffix of the names of the backing topics.",
+						Optional:            true,
+					},
+				},
+			},
+			"migrations": schema.SingleNestedAttribute{
+				MarkdownDescription: "Writes every mutating statement to a [ksql-migrations](https://docs.ksqldb.io/en/latest/operate-and-deploy/migrations-tool/) directory, e.g. to have the statements reviewed or applied by a release pipeline.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"directory": schema.StringAttribute{
+						MarkdownDescription: "The directory the migration files, e.g. `V000001__create_stream_orders.sql`, are written to. Versions continue after the highest version already in the directory.",
+						Required:            true,
+					},
+					"execute": schema.BoolAttribute{
+						MarkdownDescription: "Whether the statements are executed in addition to being written. Only statements which succeeded are written then. Defaults to `false`, in which case nothing is changed on the server and the state is derived from the configuration.",
+						Optional:            true,
+					},
+				},
+			},
 		},
 	}
 }
 
-// Configure prepares a HashiCups API client for data sources and resources.
+// Configure prepares a ksqlDB API client for data sources and resources.
 func (p *KsqldbProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
-	// Check environment variables
-	url := os.Getenv("KSQLDB_URL")
-	username := os.Getenv("KSQLDB_USERNAME")
-	password := os.Getenv("KSQLDB_PASSWORD")
-
 	var data KsqldbProviderModel
 
 	// Read configuration data into model
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 
+	// Create data/clients and persist to resp.DataSourceData and
+	// resp.ResourceData as appropriate.
+	client, diags := newClientFromConfig(ctx, p.version, data)
+	resp.Diagnostics.Append(diags...)
+
+	resp.DataSourceData = client
+	resp.ResourceData = client
+}
+
+// newClientFromConfig creates a client from the provider configuration, falling back to environment variables
+// for any attribute which is not configured.
+func newClientFromConfig(ctx context.Context, version string, data KsqldbProviderModel) (*Client, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	// Check environment variables
+	urls := splitUrls(os.Getenv("KSQLDB_URL"))
+	username := os.Getenv("KSQLDB_USERNAME")
+	password := os.Getenv("KSQLDB_PASSWORD")
+
 	// Check configuration data, which should take precedence over
 	// environment variable data, if found.
 	if data.Url.ValueString() != "" {
-		url = data.Url.ValueString()
+		urls = []string{data.Url.ValueString()}
+	}
+	if !data.Urls.IsNull() && !data.Urls.IsUnknown() {
+		urls = nil
+		diags.Append(data.Urls.ElementsAs(ctx, &urls, false)...)
 	}
 	if data.Username.ValueString() != "" {
 		username = data.Username.ValueString()
@@ -91,20 +292,82 @@ func (p *KsqldbProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		password = data.Password.ValueString()
 	}
 
-	if url == "" {
-		resp.Diagnostics.AddError(
+	if len(urls) == 0 {
+		diags.AddError(
 			"Missing URL Configuration",
 			"While configuring the provider, the ksqlDB URL was not found in "+
 				"the KSQLDB_URL environment variable or provider "+
-				"configuration block url attribute.",
+				"configuration block url or urls attribute.",
 		)
 		// Not returning early allows the logic to collect all errors.
 	}
 
-	// Create data/clients and persist to resp.DataSourceData and
-	// resp.ResourceData as appropriate.
-	client := NewClient(&url, &username, &password)
-	resp.ResourceData = client
+	client := NewClusterClient(urls, username, password)
+	client.schemaRegistry = newSchemaRegistryClientFromConfig(data.SchemaRegistry)
+	client.readOnly = os.Getenv("KSQLDB_READ_ONLY") == "true"
+	if !isUnset(data.ReadOnly) {
+		client.readOnly = data.ReadOnly.ValueBool()
+	}
+	client.defaults = StreamDefaults{
+		KeyFormat:   data.DefaultKeyFormat.ValueString(),
+		ValueFormat: data.DefaultValueFormat.ValueString(),
+	}
+	if !isUnset(data.DefaultReplicas) {
+		replicas := data.DefaultReplicas.ValueInt64()
+		client.defaults.Replicas = &replicas
+	}
+	if data.Naming != nil {
+		client.naming = NamingConvention{
+			ObjectPrefix: data.Naming.ObjectPrefix.ValueString(),
+			ObjectSuffix: data.Naming.ObjectSuffix.ValueString(),
+			TopicPrefix:  data.Naming.TopicPrefix.ValueString(),
+			TopicSuffix:  data.Naming.TopicSuffix.ValueString(),
+		}
+	}
+	if !isUnset(data.DefaultStreamsProperties) {
+		diags.Append(data.DefaultStreamsProperties.ElementsAs(ctx, &client.defaults.StreamsProperties, false)...)
+	}
+	settings, settingsDiags := httpSettingsFromConfig(ctx, data)
+	diags.Append(settingsDiags...)
+	client.configureHTTP(settings, version)
+
+	if !isUnset(data.LogBodyLimit) {
+		limit := int(data.LogBodyLimit.ValueInt64())
+		client.logBodyLimit = &limit
+	}
+	if data.Migrations != nil {
+		client.migrations = NewMigrationWriter(data.Migrations.Directory.ValueString())
+		client.executeMigrations = data.Migrations.Execute.ValueBool()
+	}
+
+	return client, diags
+}
+
+// newSchemaRegistryClientFromConfig creates a Schema Registry client if a Schema Registry URL is configured
+// in the provider or the environment.
+func newSchemaRegistryClientFromConfig(data *SchemaRegistryModel) *SchemaRegistryClient {
+
+	url := os.Getenv("KSQLDB_SCHEMA_REGISTRY_URL")
+	username := os.Getenv("KSQLDB_SCHEMA_REGISTRY_USERNAME")
+	password := os.Getenv("KSQLDB_SCHEMA_REGISTRY_PASSWORD")
+
+	if data != nil {
+		if data.Url.ValueString() != "" {
+			url = data.Url.ValueString()
+		}
+		if data.Username.ValueString() != "" {
+			username = data.Username.ValueString()
+		}
+		if data.Password.ValueString() != "" {
+			password = data.Password.ValueString()
+		}
+	}
+
+	if url == "" {
+		return nil
+	}
+
+	return NewSchemaRegistryClient(url, username, password)
 }
 
 // Resources defines the resources implemented in the provider.
@@ -116,5 +379,40 @@ func (p *KsqldbProvider) Resources(_ context.Context) []func() resource.Resource
 
 // DataSources defines the data sources implemented in the provider.
 func (p *KsqldbProvider) DataSources(_ context.Context) []func() datasource.DataSource {
-	return nil
+	return []func() datasource.DataSource{
+		NewSchemaDataSource,
+		NewServerInfoDataSource,
+		NewHealthDataSource,
+	}
+}
+
+// httpSettingsFromConfig returns the settings of the HTTP client from the provider configuration.
+func httpSettingsFromConfig(ctx context.Context, data KsqldbProviderModel) (HTTPSettings, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	settings := HTTPSettings{
+		UserAgent:           data.UserAgent.ValueString(),
+		MaxIdleConnsPerHost: int(data.MaxIdleConnections.ValueInt64()),
+		MaxConnsPerHost:     int(data.MaxConnectionsPerHost.ValueInt64()),
+	}
+
+	if !isUnset(data.RequestTimeout) {
+		settings.Timeout = time.Duration(data.RequestTimeout.ValueInt64()) * time.Second
+	}
+
+	if !isUnset(data.ProxyUrl) {
+		proxyUrl, err := url.Parse(data.ProxyUrl.ValueString())
+		if err != nil || proxyUrl.Scheme == "" || proxyUrl.Host == "" {
+			diags.AddAttributeError(path.Root("proxy_url"), "Invalid Proxy URL",
+				fmt.Sprintf("The proxy URL '%s' must be an absolute URL like http://proxy.example.com:3128.", data.ProxyUrl.ValueString()))
+		} else {
+			settings.ProxyURL = proxyUrl
+		}
+	}
+
+	if !isUnset(data.Headers) {
+		diags.Append(data.Headers.ElementsAs(ctx, &settings.Headers, false)...)
+	}
+
+	return settings, diags
 }
@@ -3,16 +3,34 @@
 
 package ksqldb
 
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"terraform-provider-ksqldb/internal/ksqldb/ksqldbtest"
+	"testing"
+)
+
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
 // acceptance testing. The factory function will be invoked for every Terraform
 // CLI command executed to create a provider server to which the CLI can
 // reattach.
-//var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-//	"scaffolding": providerserver.NewProtocol6WithError(New("test")()),
-//}
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"ksqldb": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+// testAccFakeServer starts a fake ksqlDB server which is stopped when the test finishes.
+func testAccFakeServer(t *testing.T) *ksqldbtest.Server {
+	server := ksqldbtest.NewServer()
+	t.Cleanup(server.Close)
+	return server
+}
 
-//func testAccPreCheck(t *testing.T) {
-// You can add code here to run prior to any test case execution, for example assertions
-// about the appropriate environment variables being set are common to see in a pre-check
-// function.
-//}
+// testAccProviderConfig returns the provider configuration pointing to the given fake server.
+func testAccProviderConfig(server *ksqldbtest.Server) string {
+	return fmt.Sprintf(`
+provider "ksqldb" {
+  url = %q
+}
+`, server.URL)
+}
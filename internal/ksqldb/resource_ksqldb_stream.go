@@ -5,8 +5,10 @@ package ksqldb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -18,19 +20,20 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"regexp"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"reflect"
 	"strconv"
 	"strings"
 	"terraform-provider-ksqldb/internal/ksqldb/customvalidator"
 	"terraform-provider-ksqldb/internal/ksqldb/modifiers"
+	"terraform-provider-ksqldb/internal/ksqldb/parser"
+	"terraform-provider-ksqldb/internal/ksqldb/util"
 )
 
-var KeySchemaIdPattern, _ = regexp.Compile(`(?i)KEY_SCHEMA_ID\s*=\s*'(\d+)'`)
-var ValueSchemaIdPattern, _ = regexp.Compile(`(?i)VALUE_SCHEMA_ID\s*=\s*'(\d+)'`)
-
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &StreamResource{}
 var _ resource.ResourceWithImportState = &StreamResource{}
+var _ resource.ResourceWithModifyPlan = &StreamResource{}
 
 func NewStreamResource() resource.Resource {
 	return &StreamResource{}
@@ -43,20 +46,24 @@ type StreamResource struct {
 
 type StreamResourceModel struct {
 	//Id              types.String `tfsdk:"id"`
-	Name            types.String `tfsdk:"name"`
-	KafkaTopic      types.String `tfsdk:"kafka_topic"`
-	Partitions      types.Int64  `tfsdk:"partitions"`
-	Replicas        types.Int64  `tfsdk:"replicas"`
-	Retention       types.Int64  `tfsdk:"retention_ms"`
-	KeyFormat       types.String `tfsdk:"key_format"`
-	ValueFormat     types.String `tfsdk:"value_format"`
-	KeySchemaId     types.Int64  `tfsdk:"key_schema_id"`
-	ValueSchemaId   types.Int64  `tfsdk:"value_schema_id"`
-	Timestamp       types.String `tfsdk:"timestamp"`
-	TimestampFormat types.String `tfsdk:"timestamp_format"`
-	Source          types.Bool   `tfsdk:"source"`
-	Query           types.String `tfsdk:"query"`
-	Properties      types.Map    `tfsdk:"properties"`
+	Name               types.String `tfsdk:"name"`
+	KafkaTopic         types.String `tfsdk:"kafka_topic"`
+	Partitions         types.Int64  `tfsdk:"partitions"`
+	Replicas           types.Int64  `tfsdk:"replicas"`
+	Retention          types.Int64  `tfsdk:"retention_ms"`
+	KeyFormat          types.String `tfsdk:"key_format"`
+	ValueFormat        types.String `tfsdk:"value_format"`
+	KeySchemaId        types.Int64  `tfsdk:"key_schema_id"`
+	ValueSchemaId      types.Int64  `tfsdk:"value_schema_id"`
+	Timestamp          types.String `tfsdk:"timestamp"`
+	TimestampFormat    types.String `tfsdk:"timestamp_format"`
+	Source             types.Bool   `tfsdk:"source"`
+	Query              types.String `tfsdk:"query"`
+	Properties         types.Map    `tfsdk:"properties"`
+	StreamsProperties  types.Object `tfsdk:"streams_properties"`
+	Statement          types.String `tfsdk:"statement"`
+	CheckCompatibility types.Bool   `tfsdk:"check_compatibility"`
+	AdoptExisting      types.Bool   `tfsdk:"adopt_existing"`
 }
 
 func (r *StreamResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -70,12 +77,13 @@ func (r *StreamResource) Schema(ctx context.Context, req resource.SchemaRequest,
 
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
-				MarkdownDescription: "Name of the stream",
+				MarkdownDescription: "Name of the stream. Names which are not enclosed by backticks are converted to uppercase by ksqlDB.",
 				Required:            true,
 				Validators: []validator.String{
-					customvalidator.Identifier(),
+					customvalidator.CaseInsensitiveIdentifier(),
 				},
 				PlanModifiers: []planmodifier.String{
+					modifiers.UseStateForEquivalentIdentifier,
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -95,14 +103,16 @@ func (r *StreamResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Optional:            true,
 				Computed:            true,
 				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
 					modifiers.RequiresReplaceIfIsSourceStreamInt64,
 				},
 			},
 			"replicas": schema.Int64Attribute{
-				MarkdownDescription: "The number of replicas in the backing topic.",
+				MarkdownDescription: "The number of replicas in the backing topic. Defaults to the provider's `default_replicas`, or to the server's default.",
 				Optional:            true,
 				Computed:            true,
 				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
 					modifiers.RequiresReplaceIfIsSourceStreamInt64,
 				},
 			},
@@ -115,22 +125,26 @@ func (r *StreamResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			},
 
 			"key_format": schema.StringAttribute{
-				MarkdownDescription: "The serialization format of the message key in the topic.",
+				MarkdownDescription: "The serialization format of the message key in the topic. Defaults to the provider's `default_key_format`, or to the server's default.",
 				Optional:            true,
+				Computed:            true,
 				Validators: []validator.String{
 					customvalidator.Format(),
 				},
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					modifiers.RequiresReplaceIfIsSourceStreamString,
 				},
 			},
 			"value_format": schema.StringAttribute{
-				MarkdownDescription: "The serialization format of the message value in the topic.",
+				MarkdownDescription: "The serialization format of the message value in the topic. Defaults to the provider's `default_value_format`, or to the server's default.",
 				Optional:            true,
+				Computed:            true,
 				Validators: []validator.String{
 					customvalidator.Format(),
 				},
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					modifiers.RequiresReplaceIfIsSourceStreamString,
 				},
 			},
@@ -167,7 +181,7 @@ func (r *StreamResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				},
 			},
 			"timestamp_format": schema.StringAttribute{
-				MarkdownDescription: "Use with the timestamp property to specify the type and format of the timestamp column. Note that the provider can't read external changes to this attribute.",
+				MarkdownDescription: "Use with the timestamp property to specify the type and format of the timestamp column.",
 				Optional:            true,
 				// TODO Validate that timestamp is set
 				PlanModifiers: []planmodifier.String{
@@ -186,7 +200,7 @@ func (r *StreamResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			},
 
 			"query": schema.StringAttribute{
-				MarkdownDescription: "The KSQL SELECT statement which this stream is materialized from. Note that the provider can't read external changes to this attribute.",
+				MarkdownDescription: "The KSQL SELECT statement which this stream is materialized from.",
 				Optional:            true,
 				Validators: []validator.String{
 					customvalidator.Query(),
@@ -194,13 +208,83 @@ func (r *StreamResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			},
 
 			"properties": schema.MapAttribute{
-				MarkdownDescription: "Map of string properties to set as the \"streamsProperties\" parameter when issuing the KSQL statement via REST",
+				MarkdownDescription: "Map of string properties to set as the \"streamsProperties\" parameter when issuing the KSQL statement via REST. Merged with the provider's `default_streams_properties`.",
 				Optional:            true,
 				ElementType:         types.StringType,
+				Validators: []validator.Map{
+					customvalidator.StreamsProperties(typedStreamsProperties),
+				},
 				PlanModifiers: []planmodifier.Map{
 					modifiers.RequiresReplaceIfIsSourceStreamMap,
 				},
 			},
+			"streams_properties": schema.SingleNestedAttribute{
+				MarkdownDescription: "Commonly used streams properties, which are validated unlike the keys of `properties`. They take precedence over `properties`.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"auto_offset_reset": schema.StringAttribute{
+						MarkdownDescription: "The `auto.offset.reset` property: where a new query starts reading the source topics, `earliest` or `latest`.",
+						Optional:            true,
+						Validators: []validator.String{
+							stringvalidator.OneOf("earliest", "latest"),
+						},
+					},
+					"processing_guarantee": schema.StringAttribute{
+						MarkdownDescription: "The `processing.guarantee` property: `at_least_once`, `exactly_once` or `exactly_once_v2`.",
+						Optional:            true,
+						Validators: []validator.String{
+							stringvalidator.OneOf("at_least_once", "exactly_once", "exactly_once_v2"),
+						},
+					},
+					"cache_max_bytes_buffering": schema.Int64Attribute{
+						MarkdownDescription: "The `cache.max.bytes.buffering` property: the maximum memory used for buffering records, in bytes.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"commit_interval_ms": schema.Int64Attribute{
+						MarkdownDescription: "The `commit.interval.ms` property: the interval in which the progress of a query is committed.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"num_stream_threads": schema.Int64Attribute{
+						MarkdownDescription: "The `num.stream.threads` property: the number of threads processing a query.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+				},
+				PlanModifiers: []planmodifier.Object{
+					modifiers.RequiresReplaceIfIsSourceStreamObject,
+				},
+			},
+
+			"statement": schema.StringAttribute{
+				MarkdownDescription: "The KSQL statement of the stream. Plans show the statement which will be sent to ksqlDB, unless it depends on values which are only known during apply, " +
+					"or the statement of an existing stream which will be adopted. " +
+					"Since Terraform requires applied values to match the plan, the planned statement is kept after apply even if ksqlDB reports it differently, e.g. reformatted. " +
+					"The statement reported by ksqlDB replaces it on the next refresh.",
+				Computed: true,
+			},
+
+			"check_compatibility": schema.BoolAttribute{
+				MarkdownDescription: "Check during planning whether a change can be applied with CREATE OR REPLACE. Changes which ksqlDB can't apply in place, e.g. to the backing topic or to any query clause but the filter and appended columns, are planned as a replacement instead of failing at apply time.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"adopt_existing": schema.BoolAttribute{
+				MarkdownDescription: "Adopt an existing stream of the same name instead of failing to create it, e.g. after the state was lost or an apply failed partially. " +
+					"The stream is only adopted if its topic, formats, schema IDs, query and other configured attributes match, otherwise planning fails with the differences. " +
+					"Columns are compared through the schema IDs and the query which define them, so streams which declare their columns are not adopted.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
 		},
 	}
 }
@@ -235,22 +319,93 @@ func (r *StreamResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	_, err := r.client.createStream(ctx, data, false, false)
+	created, err := r.client.createStream(ctx, data)
+	var readBack *ReadBackError
+	if errors.As(err, &readBack) {
+		r.recordCreatedStream(ctx, data, err, resp)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.Append(diag.NewErrorDiagnostic(err.Error(), err.Error()))
 		return
 	}
 
+	planned := data.Statement
+
+	if r.client.dryRun() {
+		setPlannedState(&data, r.client.defaults, types.StringValue(created.Statement))
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
 	err = doReadInternal(ctx, &data, r.client)
 	if err != nil {
-		resp.Diagnostics.Append(diag.NewErrorDiagnostic(err.Error(), err.Error()))
+		r.recordCreatedStream(ctx, data, err, resp)
 		return
 	}
 
+	keepPlannedStatement(&data, planned)
+
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// recordCreatedStream saves a stream which was created but could not be read back as planned, so that it is
+// neither orphaned in ksqlDB nor replaced by the next apply. The next refresh reads the attributes computed by ksqlDB.
+func (r *StreamResource) recordCreatedStream(ctx context.Context, data StreamResourceModel, err error, resp *resource.CreateResponse) {
+
+	setPlannedState(&data, r.client.defaults, types.StringNull())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	resp.Diagnostics.AddWarning(
+		"Stream Created But Not Read",
+		fmt.Sprintf("The stream %s was created, but reading it afterwards failed: %s\n\n"+
+			"It has been saved to the state as planned, the next refresh reads it from ksqlDB.", data.Name.ValueString(), err),
+	)
+}
+
+// setPlannedState derives the computed attributes of a stream from its configuration when there is nothing to
+// read back from ksqlDB, e.g. because the statement was only written to the migrations. Unknown statements are
+// set to the given one.
+func setPlannedState(data *StreamResourceModel, defaults StreamDefaults, statement types.String) {
+
+	applied := defaults.apply(*data)
+
+	if data.KeyFormat.IsUnknown() {
+		data.KeyFormat = types.StringNull()
+		if !applied.KeyFormat.IsUnknown() {
+			data.KeyFormat = applied.KeyFormat
+		}
+	}
+	if data.ValueFormat.IsUnknown() {
+		data.ValueFormat = types.StringNull()
+		if !applied.ValueFormat.IsUnknown() {
+			data.ValueFormat = applied.ValueFormat
+		}
+	}
+	if data.Replicas.IsUnknown() {
+		data.Replicas = types.Int64Null()
+		if !applied.Replicas.IsUnknown() {
+			data.Replicas = applied.Replicas
+		}
+	}
+	if data.Partitions.IsUnknown() {
+		data.Partitions = types.Int64Null()
+	}
+	if data.Statement.IsUnknown() {
+		data.Statement = statement
+	}
+}
+
+// keepPlannedStatement keeps the statement shown in the plan after apply, since Terraform requires applied
+// values to match the plan. The statement reported by ksqlDB replaces it on the next refresh, as documented
+// for the statement attribute.
+func keepPlannedStatement(data *StreamResourceModel, planned types.String) {
+	if !planned.IsNull() && !planned.IsUnknown() {
+		data.Statement = planned
+	}
+}
+
 func (r *StreamResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data StreamResourceModel
 
@@ -262,6 +417,11 @@ func (r *StreamResource) Read(ctx context.Context, req resource.ReadRequest, res
 	}
 
 	err := doReadInternal(ctx, &data, r.client)
+	if isNotFound(err) && r.client.dryRun() {
+		// the stream may only exist in the migrations, which haven't been applied yet
+		tflog.Warn(ctx, fmt.Sprintf("Keeping the state of stream %s: %s", data.Name.ValueString(), err))
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.Append(diag.NewErrorDiagnostic(err.Error(), err.Error()))
 		return
@@ -281,23 +441,56 @@ func doReadInternal(ctx context.Context, data *StreamResourceModel, client *Clie
 		return err
 	}
 
-	data.Name = types.StringValue(stream.Name)
+	return setFromSource(data, stream)
+}
+
+// setFromSource sets the attributes of the stream from its description by ksqlDB.
+func setFromSource(data *StreamResourceModel, stream *Source) error {
+
+	name := data.Name.ValueString()
+
+	// keep the configured spelling of the name as long as it refers to the same stream to avoid perpetual diffs
+	if util.NormalizeIdentifier(name) != stream.Name {
+		data.Name = types.StringValue(util.QuoteIdentifier(stream.Name))
+	}
 	data.KafkaTopic = types.StringValue(stream.Topic)
 	data.Partitions = types.Int64Value(stream.Partitions)
 	data.Replicas = types.Int64Value(stream.Replication)
 	data.KeyFormat = types.StringValue(stream.KeyFormat)
 	data.ValueFormat = types.StringValue(stream.ValueFormat)
 
-	err = setSchemaIds(data, stream.Statement, true, KeySchemaIdPattern)
+	// everything else can't be found in the response json but must be parsed from the ksql statement
+	statement, err := parser.Parse(stream.Statement)
+	if err != nil {
+		return err
+	}
+
+	data.KeySchemaId, err = int64Property(statement, "KEY_SCHEMA_ID")
+	if err != nil {
+		return err
+	}
+	data.ValueSchemaId, err = int64Property(statement, "VALUE_SCHEMA_ID")
 	if err != nil {
 		return err
 	}
-	err = setSchemaIds(data, stream.Statement, false, ValueSchemaIdPattern)
+	data.Retention, err = int64Property(statement, "RETENTION_MS")
 	if err != nil {
 		return err
 	}
+	data.TimestampFormat = stringProperty(statement, "TIMESTAMP_FORMAT")
+	data.Source = types.BoolValue(statement.Source)
+	data.Statement = types.StringValue(stream.Statement)
 
-	setTimestamp(data, stream)
+	setTimestamp(data, stream, statement)
+	setQuery(data, statement)
+
+	// the check is a setting of the resource only, imported streams start with the default
+	if data.CheckCompatibility.IsNull() {
+		data.CheckCompatibility = types.BoolValue(false)
+	}
+	if data.AdoptExisting.IsNull() {
+		data.AdoptExisting = types.BoolValue(false)
+	}
 
 	return nil
 }
@@ -312,13 +505,35 @@ func (r *StreamResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	var state StreamResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// settings of the provider don't change the stream
+	if onlyLocalChanges(state, data) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
 	// update stream
-	_, err := r.client.updateStream(ctx, data, false)
+	updated, err := r.client.updateStream(ctx, data)
 	if err != nil {
 		resp.Diagnostics.Append(diag.NewErrorDiagnostic(err.Error(), err.Error()))
 		return
 	}
 
+	planned := data.Statement
+
+	if r.client.dryRun() {
+		setPlannedState(&data, r.client.defaults, types.StringValue(updated.Statement))
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
 	// read stream again in order to refresh state
 	err = doReadInternal(ctx, &data, r.client)
 	if err != nil {
@@ -326,6 +541,8 @@ func (r *StreamResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	keepPlannedStatement(&data, planned)
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -349,71 +566,287 @@ func (r *StreamResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	}
 }
 
-func (r *StreamResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	if strings.ToUpper(req.ID) != req.ID {
-		resp.Diagnostics.Append(diag.NewErrorDiagnostic("Invalid name", "The name must be specified in uppercase"))
+func (r *StreamResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+
+	var state StreamResourceModel
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if req.Plan.Raw.IsNull() {
+		r.checkReadOnly([]string{dropStreamKsql(state.Name.ValueString())}, state, resp)
 		return
 	}
 
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	var plan StreamResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	creating := req.State.Raw.IsNull()
+
+	// the framework marks the statement unknown whenever the configuration differs from the state, even if the
+	// attribute plan modifiers resolved the difference, e.g. a name which only differs in case, or if only
+	// settings of the provider changed, which are applied without a statement
+	local := !creating && onlyLocalChanges(state, plan)
+	if local && plan.Statement.IsUnknown() {
+		plan.Statement = state.Statement
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("statement"), state.Statement)...)
+	}
+
+	changed := !req.State.Raw.Equal(resp.Plan.Raw)
+
+	if changed {
+		r.checkServerVersion(ctx, plan, resp)
+	}
+
+	r.checkNaming(creating, state, plan, resp)
+
+	// the compatibility check only applies to updates
+	if !creating && plan.CheckCompatibility.ValueBool() {
+		checkCompatibility(ctx, state, plan, resp)
+	}
+
+	if !changed || r.client == nil {
+		return
+	}
+
+	// report differences to an existing stream before apply, values which are unknown until then can't be compared
+	var adopted *Source
+	if creating && plan.AdoptExisting.ValueBool() && req.Config.Raw.IsFullyKnown() {
+		adopted = r.checkAdoption(ctx, plan, resp)
+	}
+
+	// a replacement creates the stream again, otherwise the statement is the one Update sends
+	payload, err := r.client.streamPayload(ctx, plan, !creating && !plannedReplacement(creating, state, plan, resp))
+	if err != nil {
+		resp.Diagnostics.Append(diag.NewErrorDiagnostic(err.Error(), err.Error()))
+		return
+	}
+
+	// values which are unknown until apply would be missing from the statement
+	if req.Config.Raw.IsFullyKnown() && !local {
+		statement := payload.Ksql
+		// nothing is sent when a stream is adopted
+		if adopted != nil {
+			statement = adopted.Statement
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("statement"), types.StringValue(statement))...)
+	}
+
+	if r.client.readOnly {
+		r.checkReadOnly(plannedStatements(creating, state, plan, payload, resp), plan, resp)
+	}
+}
+
+func checkCompatibility(ctx context.Context, state StreamResourceModel, plan StreamResourceModel, resp *resource.ModifyPlanResponse) {
+
+	check := checkUpgrade(state, plan)
+
+	switch check.compatibility {
+	case upgradeRequiresReplace:
+		tflog.Info(ctx, fmt.Sprintf("Stream %s can't be upgraded in place: %s", plan.Name.ValueString(), strings.Join(check.reasons, "; ")))
+		resp.RequiresReplace.Append(check.paths...)
+	case upgradeInvalid:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("query"),
+			"Invalid Query Change",
+			fmt.Sprintf("ksqlDB would reject the change of stream %s: %s", plan.Name.ValueString(), strings.Join(check.reasons, "; ")),
+		)
+	}
+}
+
+// plannedStatements returns the statements which applying the plan would execute.
+func plannedStatements(creating bool, state StreamResourceModel, plan StreamResourceModel, payload *Payload, resp *resource.ModifyPlanResponse) []string {
+
+	if plannedReplacement(creating, state, plan, resp) {
+		return []string{dropStreamKsql(state.Name.ValueString()), payload.Ksql}
+	}
+
+	if !creating && onlyLocalChanges(state, plan) {
+		return nil
+	}
+
+	return []string{payload.Ksql}
+}
+
+// onlyLocalChanges returns whether the plan only changes settings of the provider like check_compatibility
+// or adopt_existing, which ksqlDB doesn't know about, so they are applied to the state without sending a statement.
+func onlyLocalChanges(state StreamResourceModel, plan StreamResourceModel) bool {
+
+	plan.Statement = state.Statement
+	plan.CheckCompatibility = state.CheckCompatibility
+	plan.AdoptExisting = state.AdoptExisting
+
+	return reflect.DeepEqual(plan, state)
+}
+
+// plannedReplacement returns whether the plan replaces the stream instead of updating it.
+func plannedReplacement(creating bool, state StreamResourceModel, plan StreamResourceModel, resp *resource.ModifyPlanResponse) bool {
+
+	// the attribute plan modifiers which require a replacement are not visible here, so check for them as well
+	return !creating && (len(resp.RequiresReplace) > 0 ||
+		!plan.Name.Equal(state.Name) || !plan.Source.Equal(state.Source) ||
+		!plan.KeySchemaId.Equal(state.KeySchemaId) || !plan.ValueSchemaId.Equal(state.ValueSchemaId) ||
+		(state.Source.ValueBool() && sourceStreamChanged(state, plan)))
+}
+
+// sourceStreamChanged returns whether any attribute changed which requires the replacement of a source stream,
+// see modifiers.RequiresReplaceIfIsSourceStreamString and its siblings. Settings of the resource like
+// check_compatibility and adopt_existing don't.
+func sourceStreamChanged(state StreamResourceModel, plan StreamResourceModel) bool {
+	return !plan.KafkaTopic.Equal(state.KafkaTopic) ||
+		!plan.Partitions.Equal(state.Partitions) ||
+		!plan.Replicas.Equal(state.Replicas) ||
+		!plan.Retention.Equal(state.Retention) ||
+		!plan.KeyFormat.Equal(state.KeyFormat) ||
+		!plan.ValueFormat.Equal(state.ValueFormat) ||
+		!plan.Timestamp.Equal(state.Timestamp) ||
+		!plan.TimestampFormat.Equal(state.TimestampFormat) ||
+		!plan.Properties.Equal(state.Properties) ||
+		!plan.StreamsProperties.Equal(state.StreamsProperties)
+}
+
+// checkReadOnly fails the plan if the provider is read-only, listing the statements which would be executed.
+func (r *StreamResource) checkReadOnly(statements []string, data StreamResourceModel, resp *resource.ModifyPlanResponse) {
+
+	if r.client == nil || !r.client.readOnly || len(statements) == 0 {
+		return
+	}
+
+	resp.Diagnostics.AddError(
+		"Read-Only Provider",
+		fmt.Sprintf("The provider is configured with read_only = true, so the changes of stream %s can't be applied. "+
+			"Applying them requires the following statements:\n\n%s", data.Name.ValueString(), strings.Join(statements, "\n")),
+	)
+}
+
+// checkServerVersion reports attributes of the planned stream which the ksqlDB server doesn't support yet.
+func (r *StreamResource) checkServerVersion(ctx context.Context, plan StreamResourceModel, resp *resource.ModifyPlanResponse) {
+
+	// the provider isn't configured yet if its configuration depends on unknown values
+	if r.client == nil {
+		return
+	}
+
+	version, err := r.client.serverVersion(ctx)
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Could not determine the ksqlDB version, skipping feature checks: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(checkStreamFeatures(version, plan)...)
+}
+
+// checkNaming checks new names of the stream and its topic against the naming convention of the provider.
+// Existing names are not checked, so streams created before the convention was introduced can still be managed.
+func (r *StreamResource) checkNaming(creating bool, state StreamResourceModel, plan StreamResourceModel, resp *resource.ModifyPlanResponse) {
+
+	if r.client == nil {
+		return
+	}
+
+	if !plan.Name.IsUnknown() && (creating || !plan.Name.Equal(state.Name)) {
+		if err := r.client.naming.checkObjectName(plan.Name.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name"), "Invalid Name", err.Error())
+		}
+	}
+
+	if !plan.KafkaTopic.IsUnknown() && (creating || !plan.KafkaTopic.Equal(state.KafkaTopic)) {
+		if err := r.client.naming.checkTopicName(plan.KafkaTopic.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("kafka_topic"), "Invalid Topic Name", err.Error())
+		}
+	}
+}
+
+func (r *StreamResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importSource(ctx, r.client, "STREAM", req, resp)
 }
 
-func setTimestamp(data *StreamResourceModel, stream *Source) {
+func setTimestamp(data *StreamResourceModel, stream *Source, statement *parser.Statement) {
 
-	timestamp := stream.Timestamp
+	// the statement contains the timestamp column as it was specified, including backticks
+	if timestamp, ok := statement.Properties["TIMESTAMP"]; ok {
+		data.Timestamp = types.StringValue(timestamp)
+		return
+	}
 
 	// if received timestamp is nil or empty, set nil in state
-	if len(timestamp) == 0 {
+	if len(stream.Timestamp) == 0 {
 		data.Timestamp = types.StringNull()
 		return
 	}
 
-	// get position of the timestamp field in the statement
-	statement := stream.Statement
-	index := strings.Index(statement, timestamp)
+	// otherwise just take the timestamp value as is
+	data.Timestamp = types.StringValue(util.QuoteIdentifier(stream.Timestamp))
+}
+
+func setQuery(data *StreamResourceModel, statement *parser.Statement) {
 
-	// check whether the timestamp field was specified with backticks in the statement
-	// if so, set the timestamp field with added backticks
-	if statement[index-1] == '`' && statement[index+len(timestamp)] == '`' {
-		newTimestamp := "`" + timestamp + "`"
-		data.Timestamp = types.StringValue(newTimestamp)
+	if statement.Query == "" {
+		data.Query = types.StringNull()
 		return
 	}
 
-	// otherwise just take the timestamp value as is
-	data.Timestamp = types.StringValue(timestamp)
+	// ksqlDB may reformat the query, so keep the configured query as long as it is equivalent
+	if !data.Query.IsNull() && !data.Query.IsUnknown() && parser.EquivalentQueries(data.Query.ValueString(), statement.Query) {
+		return
+	}
+
+	data.Query = types.StringValue(statement.Query)
 }
 
-func setSchemaIds(data *StreamResourceModel, statement string, isKey bool, pattern *regexp.Regexp) error {
+func stringProperty(statement *parser.Statement, property string) types.String {
+	if value, ok := statement.Properties[property]; ok {
+		return types.StringValue(value)
+	}
+	return types.StringNull()
+}
 
-	// value schema id can't be found in response json but must be parsed from ksql statement
-	schemaIdMatches := pattern.FindStringSubmatch(statement)
+func int64Property(statement *parser.Statement, property string) (types.Int64, error) {
 
-	if len(schemaIdMatches) > 1 {
+	value, ok := statement.Properties[property]
+	if !ok {
+		return types.Int64Null(), nil
+	}
 
-		schemaId := schemaIdMatches[1]
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return types.Int64Null(), fmt.Errorf("invalid value '%s' for property %s: %w", value, property, err)
+	}
 
-		// convert the found id to integer
-		if schemaIdInt, err := strconv.Atoi(schemaId); err == nil {
+	return types.Int64Value(i), nil
+}
 
-			// set in state object
-			if isKey {
-				data.KeySchemaId = types.Int64Value(int64(schemaIdInt))
-			} else {
-				data.ValueSchemaId = types.Int64Value(int64(schemaIdInt))
-			}
-		} else {
-			return err
-		}
+// checkAdoption fails the plan if an existing stream of the same name can't be adopted because its definition
+// differs from the configuration. It returns the stream which will be adopted, if any.
+func (r *StreamResource) checkAdoption(ctx context.Context, plan StreamResourceModel, resp *resource.ModifyPlanResponse) *Source {
 
-	} else {
+	existing, err := r.client.describe(ctx, plan.Name.ValueString())
+	if err != nil {
+		// there is nothing to adopt, the stream will be created
+		return nil
+	}
 
-		if isKey {
-			data.KeySchemaId = types.Int64Null()
-		} else {
-			data.ValueSchemaId = types.Int64Null()
-		}
+	differences, err := streamDifferences(r.client.defaults.apply(plan), existing)
+	if err != nil {
+		resp.Diagnostics.Append(diag.NewErrorDiagnostic(err.Error(), err.Error()))
+		return nil
 	}
 
-	return nil
+	if len(differences) > 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("adopt_existing"), "Existing Stream Differs",
+			fmt.Sprintf("The stream %s already exists, but can't be adopted because its definition differs from the configuration:\n  %s",
+				plan.Name.ValueString(), strings.Join(differences, "\n  ")))
+		return nil
+	}
+
+	return existing
 }
@@ -0,0 +1,739 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ksqldb
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAccStreamResource(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server) + `
+resource "ksqldb_stream" "test" {
+  name            = "ORDERS"
+  kafka_topic     = "orders"
+  partitions      = 3
+  key_format      = "AVRO"
+  value_format    = "AVRO"
+  value_schema_id = 7
+  timestamp       = "CREATED_AT"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "name", "ORDERS"),
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "kafka_topic", "orders"),
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "partitions", "3"),
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "replicas", "1"),
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "value_schema_id", "7"),
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "timestamp", "CREATED_AT"),
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "source", "false"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:                         "ksqldb_stream.test",
+				ImportState:                          true,
+				ImportStateId:                        "orders",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+				// unquoted names are imported in uppercase
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if name := states[0].Attributes["name"]; name != "ORDERS" {
+						return fmt.Errorf("expected name ORDERS, got %s", name)
+					}
+					return nil
+				},
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(server) + `
+resource "ksqldb_stream" "test" {
+  name            = "ORDERS"
+  kafka_topic     = "orders"
+  partitions      = 3
+  key_format      = "AVRO"
+  value_format    = "AVRO"
+  value_schema_id = 7
+  timestamp       = "CREATED_AT"
+  retention_ms    = 86400000
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "retention_ms", "86400000"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccStreamResource_materialized(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "ksqldb_stream" "input" {
+  name         = "INPUT"
+  kafka_topic  = "input"
+  key_format   = "AVRO"
+  value_format = "AVRO"
+}
+
+resource "ksqldb_stream" "filtered" {
+  name         = "` + "`filtered-v2`" + `"
+  kafka_topic  = "filtered"
+  key_format   = "AVRO"
+  value_format = "AVRO"
+  query        = "SELECT * FROM ${ksqldb_stream.input.name} WHERE ID IS NOT NULL"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ksqldb_stream.filtered", "name", "`filtered-v2`"),
+					resource.TestCheckResourceAttr("ksqldb_stream.filtered", "query", "SELECT * FROM INPUT WHERE ID IS NOT NULL"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccStreamResource_unsupportedVersion(t *testing.T) {
+	server := testAccFakeServer(t)
+	server.SetVersion("7.1.0")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "ksqldb_stream" "test" {
+  name            = "ORDERS"
+  kafka_topic     = "orders"
+  value_format    = "AVRO"
+  value_schema_id = 7
+}
+`,
+				ExpectError: regexp.MustCompile(`value_schema_id requires ksqlDB >= 0.24, but the server runs ksqlDB\s+0.23.0`),
+			},
+		},
+	})
+}
+
+func TestAccStreamResource_readBackFailure(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	interval := describeRetryInterval
+	describeRetryInterval = time.Millisecond
+	t.Cleanup(func() { describeRetryInterval = interval })
+
+	// fail every attempt to read back the created stream, like a node which hasn't applied the CREATE yet
+	var failures atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ksql" && failures.Load() < describeAttempts {
+			body, _ := io.ReadAll(r.Body)
+			if strings.Contains(string(body), "DESCRIBE") && server.Exec("DESCRIBE ORDERS;") == nil {
+				failures.Add(1)
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"@type":"statement_error","error_code":40001,"message":"Could not find STREAM/TABLE 'ORDERS' in the Metastore"}`))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(proxy.Close)
+
+	config := fmt.Sprintf(`
+provider "ksqldb" {
+  url = %q
+}
+
+resource "ksqldb_stream" "test" {
+  name         = "ORDERS"
+  kafka_topic  = "orders"
+  key_format   = "AVRO"
+  value_format = "AVRO"
+}
+`, proxy.URL)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// the stream is kept as planned instead of being tainted
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "kafka_topic", "orders"),
+					resource.TestCheckNoResourceAttr("ksqldb_stream.test", "partitions"),
+				),
+			},
+			// the next refresh reads it without replacing it
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "partitions", "1"),
+					func(*terraform.State) error {
+						for _, request := range server.Requests() {
+							if strings.HasPrefix(request.Ksql, "DROP STREAM") {
+								return fmt.Errorf("expected the created stream to be kept, got %s", request.Ksql)
+							}
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// Names are imported in uppercase, which used to plan a replacement of the stream with a lowercase name.
+func TestAccStreamResource_importLowercase(t *testing.T) {
+	server := testAccFakeServer(t)
+	if err := server.Exec("CREATE STREAM ORDERS WITH (KAFKA_TOPIC='orders', KEY_FORMAT='AVRO', VALUE_FORMAT='AVRO');"); err != nil {
+		t.Fatal(err)
+	}
+
+	config := testAccProviderConfig(server) + `
+resource "ksqldb_stream" "test" {
+  name         = "orders"
+  kafka_topic  = "orders"
+  key_format   = "AVRO"
+  value_format = "AVRO"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             config,
+				ResourceName:       "ksqldb_stream.test",
+				ImportState:        true,
+				ImportStateId:      "orders",
+				ImportStatePersist: true,
+			},
+			{
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
+func TestAccStreamResource_providerDefaults(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "ksqldb" {
+  url                  = %q
+  default_key_format   = "KAFKA"
+  default_value_format = "AVRO"
+  default_replicas     = 3
+  default_streams_properties = {
+    "auto.offset.reset"         = "earliest"
+    "ksql.streams.num.threads" = "2"
+  }
+}
+
+resource "ksqldb_stream" "test" {
+  name        = "ORDERS"
+  kafka_topic = "orders"
+  properties = {
+    "auto.offset.reset" = "latest"
+  }
+}
+`, server.URL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "key_format", "KAFKA"),
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "value_format", "AVRO"),
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "replicas", "3"),
+					func(*terraform.State) error {
+						for _, request := range server.Requests() {
+							if !strings.HasPrefix(request.Ksql, "CREATE") {
+								continue
+							}
+							if request.Properties["auto.offset.reset"] != "latest" || request.Properties["ksql.streams.num.threads"] != "2" {
+								return fmt.Errorf("unexpected streams properties: %v", request.Properties)
+							}
+							return nil
+						}
+						return fmt.Errorf("expected a CREATE statement")
+					},
+				),
+			},
+		},
+	})
+}
+
+// The default replicas used to be sent with every update, since unset replicas are unknown in the plan of an update,
+// which fails against an existing topic with another replication.
+func TestAccStreamResource_providerDefaultsUpdate(t *testing.T) {
+	server := testAccFakeServer(t)
+	if err := server.Exec("CREATE STREAM ORDERS WITH (KAFKA_TOPIC='orders', KEY_FORMAT='KAFKA', VALUE_FORMAT='AVRO');"); err != nil {
+		t.Fatal(err)
+	}
+
+	config := func(retention string) string {
+		return fmt.Sprintf(`
+provider "ksqldb" {
+  url              = %q
+  default_replicas = 3
+}
+
+resource "ksqldb_stream" "test" {
+  name         = "ORDERS"
+  kafka_topic  = "orders"
+  key_format   = "KAFKA"
+  value_format = "AVRO"
+%s}
+`, server.URL, retention)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             config(""),
+				ResourceName:       "ksqldb_stream.test",
+				ImportState:        true,
+				ImportStateId:      "ORDERS",
+				ImportStatePersist: true,
+			},
+			{
+				Config: config("  retention_ms = 86400000\n"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "replicas", "1"),
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "retention_ms", "86400000"),
+					func(*terraform.State) error {
+						for _, request := range server.Requests() {
+							if strings.HasPrefix(request.Ksql, "CREATE OR REPLACE") && strings.Contains(request.Ksql, "REPLICAS = 3") {
+								return fmt.Errorf("expected the default replicas not to be sent with an update, got %s", request.Ksql)
+							}
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccStreamResource_naming(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	provider := fmt.Sprintf(`
+provider "ksqldb" {
+  url = %q
+  naming = {
+    object_prefix = "DEV_"
+    topic_prefix  = "payments."
+  }
+}
+`, server.URL)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: provider + `
+resource "ksqldb_stream" "test" {
+  name         = "orders"
+  kafka_topic  = "orders"
+  key_format   = "AVRO"
+  value_format = "AVRO"
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)the name ORDERS must start with 'DEV_'.*the topic name orders must start\s+with\s+'payments.'`),
+			},
+			{
+				Config: provider + `
+resource "ksqldb_stream" "test" {
+  name         = "dev_orders"
+  kafka_topic  = "payments.orders"
+  key_format   = "AVRO"
+  value_format = "AVRO"
+}
+`,
+				Check: resource.TestCheckResourceAttr("ksqldb_stream.test", "name", "dev_orders"),
+			},
+		},
+	})
+}
+
+func TestAccStreamResource_streamsProperties(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	stream := func(autoOffsetReset string) string {
+		return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "ksqldb_stream" "test" {
+  name         = "ORDERS"
+  kafka_topic  = "orders"
+  key_format   = "AVRO"
+  value_format = "AVRO"
+  properties = {
+    "auto.offset.reset"    = "latest"
+    "ksql.query.pull.enabled" = "true"
+  }
+  streams_properties = {
+    auto_offset_reset  = %q
+    num_stream_threads = 2
+  }
+}
+`, autoOffsetReset)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      stream("earlyest"),
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+			{
+				Config: stream("earliest"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "streams_properties.num_stream_threads", "2"),
+					func(*terraform.State) error {
+						requests := server.Requests()
+						for _, request := range requests {
+							if !strings.HasPrefix(request.Ksql, "CREATE") {
+								continue
+							}
+							if request.Properties["auto.offset.reset"] != "earliest" || request.Properties["num.stream.threads"] != "2" ||
+								request.Properties["ksql.query.pull.enabled"] != "true" {
+								return fmt.Errorf("unexpected streams properties: %v", request.Properties)
+							}
+							return nil
+						}
+						return fmt.Errorf("expected a CREATE statement")
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccStreamResource_readOnly(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	config := func(readOnly bool, partitions int) string {
+		return fmt.Sprintf(`
+provider "ksqldb" {
+  url       = %q
+  read_only = %t
+}
+
+resource "ksqldb_stream" "test" {
+  name         = "ORDERS"
+  kafka_topic  = "orders"
+  partitions   = %d
+  key_format   = "AVRO"
+  value_format = "AVRO"
+}
+`, server.URL, readOnly, partitions)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(false, 1),
+			},
+			// reading is allowed
+			{
+				Config:   config(true, 1),
+				PlanOnly: true,
+			},
+			{
+				Config:      config(true, 3),
+				ExpectError: regexp.MustCompile(`(?s)Read-Only Provider.*CREATE OR REPLACE STREAM ORDERS WITH \(KAFKA_TOPIC = 'orders', PARTITIONS\s+=\s+'3'`),
+			},
+			{
+				Config: config(false, 1),
+			},
+		},
+	})
+}
+
+func TestAccStreamResource_statement(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	config := testAccProviderConfig(server) + `
+resource "ksqldb_stream" "test" {
+  name         = "ORDERS"
+  kafka_topic  = "orders"
+  key_format   = "AVRO"
+  value_format = "AVRO"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// the statement which was sent
+			{
+				Config: config,
+				Check: resource.TestCheckResourceAttr("ksqldb_stream.test", "statement",
+					"CREATE OR REPLACE STREAM ORDERS WITH (KAFKA_TOPIC = 'orders', KEY_FORMAT = 'AVRO', VALUE_FORMAT = 'AVRO');"),
+			},
+			// refreshing the statement reported by ksqlDB doesn't cause a diff
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// Changing only settings of the provider used to send CREATE OR REPLACE, which ksqlDB rejects for source streams.
+// Changing only settings of the provider used to send CREATE OR REPLACE, which ksqlDB rejects for source streams.
+func TestAccStreamResource_localSettings(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	config := func(settings string) string {
+		return testAccProviderConfig(server) + `
+resource "ksqldb_stream" "test" {
+  name         = "ORDERS"
+  kafka_topic  = "orders"
+  key_format   = "AVRO"
+  value_format = "AVRO"
+  source       = true
+` + settings + `}
+`
+	}
+
+	onlyCreated := func(*terraform.State) error {
+		var statements []string
+		for _, request := range server.Requests() {
+			if strings.HasPrefix(request.Ksql, "CREATE") || strings.HasPrefix(request.Ksql, "DROP") {
+				statements = append(statements, request.Ksql)
+			}
+		}
+		if len(statements) != 1 {
+			return fmt.Errorf("expected only the stream to be created, got %q", statements)
+		}
+		return nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+			},
+			{
+				Config: config("  check_compatibility = true\n"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "check_compatibility", "true"),
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "statement",
+						"CREATE SOURCE STREAM ORDERS WITH (KAFKA_TOPIC = 'orders', KEY_FORMAT = 'AVRO', VALUE_FORMAT = 'AVRO');"),
+					onlyCreated,
+				),
+			},
+			{
+				Config: config("  check_compatibility = true\n  adopt_existing      = true\n"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "adopt_existing", "true"),
+					onlyCreated,
+				),
+			},
+		},
+	})
+}
+
+func TestAccStreamResource_migrations(t *testing.T) {
+	server := testAccFakeServer(t)
+	directory := t.TempDir()
+
+	config := fmt.Sprintf(`
+provider "ksqldb" {
+  url = %q
+  migrations = {
+    directory = %q
+  }
+}
+
+resource "ksqldb_stream" "test" {
+  name         = "ORDERS"
+  kafka_topic  = "orders"
+  key_format   = "AVRO"
+  value_format = "AVRO"
+  properties = {
+    "auto.offset.reset" = "earliest"
+  }
+}
+`, server.URL, directory)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			_, err := os.Stat(filepath.Join(directory, "V000002__drop_stream_orders.sql"))
+			return err
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "key_format", "AVRO"),
+					resource.TestCheckNoResourceAttr("ksqldb_stream.test", "partitions"),
+					func(*terraform.State) error {
+						migration, err := os.ReadFile(filepath.Join(directory, "V000001__create_stream_orders.sql"))
+						if err != nil {
+							return err
+						}
+						expected := "SET 'auto.offset.reset'='earliest';\n" +
+							"CREATE OR REPLACE STREAM ORDERS WITH (KAFKA_TOPIC = 'orders', KEY_FORMAT = 'AVRO', VALUE_FORMAT = 'AVRO');\n" +
+							"UNSET 'auto.offset.reset';\n"
+						if string(migration) != expected {
+							return fmt.Errorf("unexpected migration:\n%s", migration)
+						}
+						for _, request := range server.Requests() {
+							if strings.HasPrefix(request.Ksql, "CREATE") {
+								return fmt.Errorf("expected the statement not to be executed, got %s", request.Ksql)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			// the stream doesn't exist on the server, but the state is kept
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccStreamResource_adoptExisting(t *testing.T) {
+	server := testAccFakeServer(t)
+	if err := server.Exec("CREATE STREAM ORDERS WITH (KAFKA_TOPIC='orders', KEY_FORMAT='AVRO', VALUE_FORMAT='AVRO');"); err != nil {
+		t.Fatal(err)
+	}
+
+	stream := func(topic string, valueFormat string) string {
+		return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "ksqldb_stream" "test" {
+  name           = "ORDERS"
+  kafka_topic    = %q
+  key_format     = "AVRO"
+  value_format   = %q
+  adopt_existing = true
+}
+`, topic, valueFormat)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      stream("orders_v2", "JSON"),
+				ExpectError: regexp.MustCompile(`(?s)kafka_topic: configured "orders_v2", existing "orders".*value_format:\s+configured "JSON", existing "AVRO"`),
+			},
+			{
+				Config: stream("orders", "AVRO"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "adopt_existing", "true"),
+					// the statement of the adopted stream, since nothing was sent
+					resource.TestCheckResourceAttr("ksqldb_stream.test", "statement", "CREATE STREAM ORDERS WITH (KAFKA_TOPIC='orders', KEY_FORMAT='AVRO', VALUE_FORMAT='AVRO');"),
+					func(*terraform.State) error {
+						for _, request := range server.Requests() {
+							if strings.HasPrefix(request.Ksql, "CREATE") {
+								return fmt.Errorf("expected the stream to be adopted, got %s", request.Ksql)
+							}
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// Any change of a source stream used to be planned as a replacement, even of settings of the resource.
+func TestPlannedStatements(t *testing.T) {
+
+	const create = "CREATE STREAM ORDERS;"
+	const drop = "DROP STREAM ORDERS;"
+
+	tests := []struct {
+		name     string
+		source   bool
+		change   func(plan *StreamResourceModel)
+		replace  bool
+		creating bool
+		none     bool
+	}{
+		{name: "creating", change: func(p *StreamResourceModel) {}, creating: true},
+		{name: "changed topic", change: func(p *StreamResourceModel) { p.KafkaTopic = types.StringValue("orders_v2") }},
+		{name: "changed name", change: func(p *StreamResourceModel) { p.Name = types.StringValue("ORDERS_V2") }, replace: true},
+		{name: "changed schema", change: func(p *StreamResourceModel) { p.ValueSchemaId = types.Int64Value(2) }, replace: true},
+		{name: "source stream, changed topic", source: true, change: func(p *StreamResourceModel) { p.KafkaTopic = types.StringValue("orders_v2") }, replace: true},
+		{name: "source stream, unknown partitions", source: true, change: func(p *StreamResourceModel) { p.Partitions = types.Int64Unknown() }, replace: true},
+		{name: "source stream, check_compatibility", source: true, change: func(p *StreamResourceModel) { p.CheckCompatibility = types.BoolValue(true) }, none: true},
+		{name: "check_compatibility", change: func(p *StreamResourceModel) { p.CheckCompatibility = types.BoolValue(true) }, none: true},
+		{name: "source stream, adopt_existing", source: true, change: func(p *StreamResourceModel) { p.AdoptExisting = types.BoolValue(true) }, none: true},
+	}
+
+	for _, test := range tests {
+		state := emptyStream("ORDERS")
+		state.KafkaTopic = types.StringValue("orders")
+		state.ValueSchemaId = types.Int64Value(1)
+		state.Source = types.BoolValue(test.source)
+
+		plan := state
+		test.change(&plan)
+
+		want := []string{create}
+		if test.replace {
+			want = []string{drop, create}
+		}
+		if test.none {
+			want = nil
+		}
+
+		got := plannedStatements(test.creating, state, plan, &Payload{Ksql: create}, &fwresource.ModifyPlanResponse{})
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %q, got %q", test.name, want, got)
+		}
+	}
+
+	// replacements required by the compatibility check
+	resp := &fwresource.ModifyPlanResponse{}
+	resp.RequiresReplace.Append(path.Root("query"))
+	state := emptyStream("ORDERS")
+	if got := plannedStatements(false, state, state, &Payload{Ksql: create}, resp); !reflect.DeepEqual(got, []string{drop, create}) {
+		t.Errorf("expected the replacement to be planned, got %q", got)
+	}
+}
@@ -0,0 +1,99 @@
+package ksqldb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SchemaRegistryClient the Schema Registry client object.
+type SchemaRegistryClient struct {
+	client    *http.Client
+	url       string
+	username  string
+	password  string
+	userAgent string
+}
+
+type SchemaRegistryError struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+type Schema struct {
+	Subject    string `json:"subject"`
+	Version    int64  `json:"version"`
+	Id         int64  `json:"id"`
+	SchemaType string `json:"schemaType"`
+	Schema     string `json:"schema"`
+}
+
+// NewSchemaRegistryClient creates a new Schema Registry client.
+func NewSchemaRegistryClient(url, username, password string) *SchemaRegistryClient {
+	return &SchemaRegistryClient{
+		client:   newHTTPClient(HTTPSettings{}),
+		url:      strings.TrimSuffix(url, "/"),
+		username: username,
+		password: password,
+	}
+}
+
+// getSchema returns the schema registered under the given subject and version. The version may be "latest".
+func (c *SchemaRegistryClient) getSchema(ctx context.Context, subject string, version string) (*Schema, error) {
+
+	endpoint := fmt.Sprintf("%s/subjects/%s/versions/%s", c.url, url.PathEscape(subject), url.PathEscape(version))
+
+	tflog.Info(ctx, fmt.Sprintf("Fetching schema: %s", endpoint))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		var obj SchemaRegistryError
+		if err := json.Unmarshal(body, &obj); err != nil || obj.Message == "" {
+			return nil, fmt.Errorf("schema registry responded with status %d", res.StatusCode)
+		}
+
+		return nil, errors.New(obj.Message)
+	}
+
+	var schema Schema
+	if err := json.Unmarshal(body, &schema); err != nil {
+		return nil, err
+	}
+
+	// Schema Registry omits the type for Avro schemas
+	if schema.SchemaType == "" {
+		schema.SchemaType = "AVRO"
+	}
+
+	return &schema, nil
+}
@@ -0,0 +1,75 @@
+package ksqldb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSchemaRegistryGetSchema(t *testing.T) {
+
+	schemaRegistry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, _ := r.BasicAuth(); username != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error_code":401,"message":"Unauthorized"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+		switch r.URL.EscapedPath() {
+		case "/subjects/orders-value/versions/latest":
+			_, _ = w.Write([]byte(`{"subject":"orders-value","version":2,"id":12,"schema":"{\"type\":\"string\"}"}`))
+		case "/subjects/orders-value/versions/1":
+			_, _ = w.Write([]byte(`{"subject":"orders-value","version":1,"id":7,"schemaType":"PROTOBUF","schema":"syntax = \"proto3\";"}`))
+		case "/subjects/shop%2Forders-value/versions/latest":
+			_, _ = w.Write([]byte(`{"subject":"shop/orders-value","version":1,"id":3,"schemaType":"JSON","schema":"{}"}`))
+		case "/subjects/broken/versions/latest":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`<html>Internal Server Error</html>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code":40401,"message":"Subject 'unknown' not found."}`))
+		}
+	}))
+	t.Cleanup(schemaRegistry.Close)
+
+	client := NewSchemaRegistryClient(schemaRegistry.URL+"/", "user", "secret")
+
+	tests := []struct {
+		subject string
+		version string
+		want    Schema
+	}{
+		{"orders-value", "latest", Schema{Subject: "orders-value", Version: 2, Id: 12, SchemaType: "AVRO", Schema: `{"type":"string"}`}},
+		{"orders-value", "1", Schema{Subject: "orders-value", Version: 1, Id: 7, SchemaType: "PROTOBUF", Schema: `syntax = "proto3";`}},
+		{"shop/orders-value", "latest", Schema{Subject: "shop/orders-value", Version: 1, Id: 3, SchemaType: "JSON", Schema: "{}"}},
+	}
+
+	for _, test := range tests {
+		got, err := client.getSchema(context.Background(), test.subject, test.version)
+		if err != nil {
+			t.Errorf("%s/%s: unexpected error: %s", test.subject, test.version, err)
+			continue
+		}
+		if *got != test.want {
+			t.Errorf("%s/%s: expected %+v, got %+v", test.subject, test.version, test.want, *got)
+		}
+	}
+
+	failures := []struct {
+		client  *SchemaRegistryClient
+		subject string
+		want    string
+	}{
+		{client, "unknown", "Subject 'unknown' not found."},
+		{client, "broken", "schema registry responded with status 500"},
+		{NewSchemaRegistryClient(schemaRegistry.URL, "user", "wrong"), "orders-value", "Unauthorized"},
+	}
+
+	for _, test := range failures {
+		_, err := test.client.getSchema(context.Background(), test.subject, "latest")
+		if err == nil || err.Error() != test.want {
+			t.Errorf("%s: expected error %q, got %v", test.subject, test.want, err)
+		}
+	}
+}
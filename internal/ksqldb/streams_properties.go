@@ -0,0 +1,78 @@
+package ksqldb
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"strconv"
+)
+
+// StreamsPropertiesModel are the commonly used streams properties, which are validated unlike the free-form
+// properties map.
+type StreamsPropertiesModel struct {
+	AutoOffsetReset        types.String `tfsdk:"auto_offset_reset"`
+	ProcessingGuarantee    types.String `tfsdk:"processing_guarantee"`
+	CacheMaxBytesBuffering types.Int64  `tfsdk:"cache_max_bytes_buffering"`
+	CommitIntervalMs       types.Int64  `tfsdk:"commit_interval_ms"`
+	NumStreamThreads       types.Int64  `tfsdk:"num_stream_threads"`
+}
+
+// typedStreamsProperties maps the keys of the typed streams properties to their attribute names.
+var typedStreamsProperties = map[string]string{
+	"auto.offset.reset":         "auto_offset_reset",
+	"processing.guarantee":      "processing_guarantee",
+	"cache.max.bytes.buffering": "cache_max_bytes_buffering",
+	"commit.interval.ms":        "commit_interval_ms",
+	"num.stream.threads":        "num_stream_threads",
+}
+
+var streamsPropertiesAttributeTypes = map[string]attr.Type{
+	"auto_offset_reset":         types.StringType,
+	"processing_guarantee":      types.StringType,
+	"cache_max_bytes_buffering": types.Int64Type,
+	"commit_interval_ms":        types.Int64Type,
+	"num_stream_threads":        types.Int64Type,
+}
+
+// streamsProperties returns the properties of a stream which are sent as streamsProperties. The typed
+// streams properties take precedence over the free-form properties map.
+func streamsProperties(ctx context.Context, data StreamResourceModel) (map[string]string, diag.Diagnostics) {
+
+	var diags diag.Diagnostics
+
+	properties := make(map[string]string, len(data.Properties.Elements()))
+	if !data.Properties.IsNull() && !data.Properties.IsUnknown() {
+		diags.Append(data.Properties.ElementsAs(ctx, &properties, false)...)
+	}
+
+	if data.StreamsProperties.IsNull() || data.StreamsProperties.IsUnknown() {
+		return properties, diags
+	}
+
+	var typed StreamsPropertiesModel
+	diags.Append(data.StreamsProperties.As(ctx, &typed, basetypes.ObjectAsOptions{})...)
+
+	values := map[string]attr.Value{
+		"auto.offset.reset":         typed.AutoOffsetReset,
+		"processing.guarantee":      typed.ProcessingGuarantee,
+		"cache.max.bytes.buffering": typed.CacheMaxBytesBuffering,
+		"commit.interval.ms":        typed.CommitIntervalMs,
+		"num.stream.threads":        typed.NumStreamThreads,
+	}
+
+	for key, value := range values {
+		if value.IsNull() || value.IsUnknown() {
+			continue
+		}
+		switch v := value.(type) {
+		case types.String:
+			properties[key] = v.ValueString()
+		case types.Int64:
+			properties[key] = strconv.FormatInt(v.ValueInt64(), 10)
+		}
+	}
+
+	return properties, diags
+}
@@ -0,0 +1,59 @@
+package ksqldb
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"testing"
+)
+
+func TestStreamsProperties(t *testing.T) {
+
+	typed := func(autoOffsetReset attr.Value, numStreamThreads attr.Value) types.Object {
+		return types.ObjectValueMust(streamsPropertiesAttributeTypes, map[string]attr.Value{
+			"auto_offset_reset":         autoOffsetReset,
+			"processing_guarantee":      types.StringNull(),
+			"cache_max_bytes_buffering": types.Int64Null(),
+			"commit_interval_ms":        types.Int64Null(),
+			"num_stream_threads":        numStreamThreads,
+		})
+	}
+
+	properties := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"auto.offset.reset":       types.StringValue("latest"),
+		"ksql.query.pull.enabled": types.StringValue("true"),
+	})
+
+	tests := []struct {
+		name              string
+		properties        types.Map
+		streamsProperties types.Object
+		want              map[string]string
+	}{
+		{"none", types.MapNull(types.StringType), types.ObjectNull(streamsPropertiesAttributeTypes), map[string]string{}},
+		{"properties", properties, types.ObjectNull(streamsPropertiesAttributeTypes),
+			map[string]string{"auto.offset.reset": "latest", "ksql.query.pull.enabled": "true"}},
+		{"typed properties take precedence", properties, typed(types.StringValue("earliest"), types.Int64Value(2)),
+			map[string]string{"auto.offset.reset": "earliest", "ksql.query.pull.enabled": "true", "num.stream.threads": "2"}},
+		{"unset typed properties", properties, typed(types.StringNull(), types.Int64Unknown()),
+			map[string]string{"auto.offset.reset": "latest", "ksql.query.pull.enabled": "true"}},
+		{"unknown properties", types.MapUnknown(types.StringType), typed(types.StringValue("earliest"), types.Int64Null()),
+			map[string]string{"auto.offset.reset": "earliest"}},
+	}
+
+	for _, test := range tests {
+		data := emptyStream("ORDERS")
+		data.Properties = test.properties
+		data.StreamsProperties = test.streamsProperties
+
+		got, diags := streamsProperties(context.Background(), data)
+		if diags.HasError() {
+			t.Errorf("%s: unexpected error: %v", test.name, diags)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+	}
+}
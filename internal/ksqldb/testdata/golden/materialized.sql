@@ -0,0 +1 @@
+CREATE OR REPLACE STREAM LARGE_ORDERS WITH (KAFKA_TOPIC = 'large-orders', VALUE_FORMAT = 'AVRO') AS SELECT * FROM ORDERS WHERE AMOUNT > 100 EMIT CHANGES;
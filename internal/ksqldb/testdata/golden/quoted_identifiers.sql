@@ -0,0 +1 @@
+CREATE OR REPLACE STREAM `orders-v2` WITH (KAFKA_TOPIC = 'orders-v2', TIMESTAMP = '`created-at`', VALUE_FORMAT = 'AVRO');
@@ -0,0 +1 @@
+CREATE OR REPLACE STREAM ORDERS WITH (KAFKA_TOPIC = 'orders', VALUE_FORMAT = 'JSON_SR', KEY_SCHEMA_ID = '6', VALUE_SCHEMA_ID = '7');
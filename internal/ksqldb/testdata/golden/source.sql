@@ -0,0 +1 @@
+CREATE SOURCE STREAM ORDERS WITH (KAFKA_TOPIC = 'orders', PARTITIONS = '6', REPLICAS = '3', RETENTION_MS = '604800000', TIMESTAMP = 'CREATED_AT', TIMESTAMP_FORMAT = 'yyyy-MM-dd''T''HH:mm:ssX', KEY_FORMAT = 'AVRO', VALUE_FORMAT = 'JSON_SR', KEY_SCHEMA_ID = '6', VALUE_SCHEMA_ID = '7');
@@ -0,0 +1 @@
+CREATE OR REPLACE STREAM ORDERS WITH (KAFKA_TOPIC = 'orders', TIMESTAMP = 'CREATED_AT', TIMESTAMP_FORMAT = 'yyyy-MM-dd''T''HH:mm:ssX', VALUE_FORMAT = 'JSON_SR');
@@ -0,0 +1 @@
+CREATE OR REPLACE STREAM ORDERS WITH (KAFKA_TOPIC = 'orders');
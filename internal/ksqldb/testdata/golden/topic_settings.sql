@@ -0,0 +1 @@
+CREATE OR REPLACE STREAM ORDERS WITH (KAFKA_TOPIC = 'orders', PARTITIONS = '6', REPLICAS = '3', RETENTION_MS = '604800000', VALUE_FORMAT = 'JSON_SR');
@@ -0,0 +1,144 @@
+package ksqldb
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"terraform-provider-ksqldb/internal/ksqldb/parser"
+)
+
+// upgradeCompatibility classifies a change of a stream according to ksqlDB's rules for CREATE OR REPLACE.
+type upgradeCompatibility int
+
+const (
+	// upgradeCompatible changes can be applied in place.
+	upgradeCompatible upgradeCompatibility = iota
+	// upgradeRequiresReplace changes can only be applied by dropping and recreating the stream.
+	upgradeRequiresReplace
+	// upgradeInvalid changes would be rejected by ksqlDB regardless of how they are applied.
+	upgradeInvalid
+)
+
+// upgradeCheck is the result of checking a change of a stream.
+type upgradeCheck struct {
+	compatibility upgradeCompatibility
+	// paths are the attributes which require a replacement.
+	paths   []path.Path
+	reasons []string
+}
+
+func (c *upgradeCheck) requireReplace(attribute string, reason string) {
+	if c.compatibility < upgradeRequiresReplace {
+		c.compatibility = upgradeRequiresReplace
+	}
+	c.paths = append(c.paths, path.Root(attribute))
+	c.reasons = append(c.reasons, reason)
+}
+
+func (c *upgradeCheck) invalid(reason string) {
+	c.compatibility = upgradeInvalid
+	c.reasons = append(c.reasons, reason)
+}
+
+// checkUpgrade checks whether a stream can be changed from state to plan by CREATE OR REPLACE.
+// Unknown values in the plan are assumed to be unchanged.
+func checkUpgrade(state StreamResourceModel, plan StreamResourceModel) *upgradeCheck {
+
+	check := &upgradeCheck{}
+
+	// the backing topic and its serialization can't be changed for an existing stream
+	attributes := []struct {
+		name  string
+		state attr.Value
+		plan  attr.Value
+	}{
+		{"kafka_topic", state.KafkaTopic, plan.KafkaTopic},
+		{"partitions", state.Partitions, plan.Partitions},
+		{"replicas", state.Replicas, plan.Replicas},
+		{"retention_ms", state.Retention, plan.Retention},
+		{"key_format", state.KeyFormat, plan.KeyFormat},
+		{"value_format", state.ValueFormat, plan.ValueFormat},
+		{"timestamp", state.Timestamp, plan.Timestamp},
+		{"timestamp_format", state.TimestampFormat, plan.TimestampFormat},
+	}
+
+	for _, a := range attributes {
+		if !a.plan.IsUnknown() && !a.plan.Equal(a.state) {
+			check.requireReplace(a.name, fmt.Sprintf("%s can't be changed in place", a.name))
+		}
+	}
+
+	if plan.Query.IsUnknown() || plan.Query.Equal(state.Query) {
+		return check
+	}
+
+	if state.Query.IsNull() || plan.Query.IsNull() {
+		check.requireReplace("query", "a stream can't be changed between being materialized from a query and not")
+		return check
+	}
+
+	checkQueryUpgrade(check, state.Query.ValueString(), plan.Query.ValueString())
+
+	return check
+}
+
+// checkQueryUpgrade checks a change of the query of a persistent query. ksqlDB allows changing the filter
+// and adding columns to the end of the projection. Everything else requires a new query.
+func checkQueryUpgrade(check *upgradeCheck, stateQuery string, planQuery string) {
+
+	if parser.EquivalentQueries(stateQuery, planQuery) {
+		return
+	}
+
+	old, errOld := parser.ParseQuery(stateQuery)
+	updated, errNew := parser.ParseQuery(planQuery)
+	if errOld != nil || errNew != nil {
+		check.requireReplace("query", "the query can't be analyzed")
+		return
+	}
+
+	// duplicate column names are rejected by ksqlDB
+	names := map[string]bool{}
+	for _, item := range updated.Projection {
+		name := parser.ColumnName(item)
+		if name == "" {
+			continue
+		}
+		if names[name] {
+			check.invalid(fmt.Sprintf("the query selects the column %s more than once", name))
+		}
+		names[name] = true
+	}
+
+	clauses := []struct {
+		name string
+		old  string
+		new  string
+	}{
+		{"FROM", old.From, updated.From},
+		{"WINDOW", old.Window, updated.Window},
+		{"GROUP BY", old.GroupBy, updated.GroupBy},
+		{"PARTITION BY", old.PartitionBy, updated.PartitionBy},
+		{"HAVING", old.Having, updated.Having},
+		{"LIMIT", old.Limit, updated.Limit},
+	}
+
+	for _, clause := range clauses {
+		if !parser.EquivalentQueries(clause.old, clause.new) {
+			check.requireReplace("query", fmt.Sprintf("the %s clause of a query can't be changed in place", clause.name))
+		}
+	}
+
+	// existing columns must be kept in their order, new columns may only be appended
+	if len(updated.Projection) < len(old.Projection) {
+		check.requireReplace("query", "columns can't be removed from a query in place")
+		return
+	}
+
+	for i, item := range old.Projection {
+		if !parser.EquivalentQueries(item, updated.Projection[i]) {
+			check.requireReplace("query", fmt.Sprintf("the column %s can't be changed in place, new columns may only be appended", item))
+			return
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package ksqldb
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"testing"
+)
+
+func TestCheckUpgrade(t *testing.T) {
+
+	const query = "SELECT ID, AMOUNT FROM ORDERS WHERE AMOUNT > 0 EMIT CHANGES"
+
+	tests := []struct {
+		name   string
+		change func(plan *StreamResourceModel)
+		want   upgradeCompatibility
+	}{
+		{"unchanged", func(p *StreamResourceModel) {}, upgradeCompatible},
+		{"reformatted query", func(p *StreamResourceModel) {
+			p.Query = types.StringValue("select id,  amount from orders where amount > 0;")
+		}, upgradeCompatible},
+		{"changed filter", func(p *StreamResourceModel) {
+			p.Query = types.StringValue("SELECT ID, AMOUNT FROM ORDERS WHERE AMOUNT > 10 EMIT CHANGES")
+		}, upgradeCompatible},
+		{"appended column", func(p *StreamResourceModel) {
+			p.Query = types.StringValue("SELECT ID, AMOUNT, UCASE(STATUS) AS STATUS FROM ORDERS WHERE AMOUNT > 0 EMIT CHANGES")
+		}, upgradeCompatible},
+		{"unknown topic", func(p *StreamResourceModel) { p.KafkaTopic = types.StringUnknown() }, upgradeCompatible},
+		{"changed topic", func(p *StreamResourceModel) { p.KafkaTopic = types.StringValue("orders_v2") }, upgradeRequiresReplace},
+		{"changed value format", func(p *StreamResourceModel) { p.ValueFormat = types.StringValue("AVRO") }, upgradeRequiresReplace},
+		{"removed column", func(p *StreamResourceModel) {
+			p.Query = types.StringValue("SELECT ID FROM ORDERS WHERE AMOUNT > 0 EMIT CHANGES")
+		}, upgradeRequiresReplace},
+		{"reordered columns", func(p *StreamResourceModel) {
+			p.Query = types.StringValue("SELECT AMOUNT, ID FROM ORDERS WHERE AMOUNT > 0 EMIT CHANGES")
+		}, upgradeRequiresReplace},
+		{"changed source", func(p *StreamResourceModel) {
+			p.Query = types.StringValue("SELECT ID, AMOUNT FROM PAYMENTS WHERE AMOUNT > 0 EMIT CHANGES")
+		}, upgradeRequiresReplace},
+		{"added partitioning", func(p *StreamResourceModel) {
+			p.Query = types.StringValue("SELECT ID, AMOUNT FROM ORDERS WHERE AMOUNT > 0 PARTITION BY ID EMIT CHANGES")
+		}, upgradeRequiresReplace},
+		{"removed query", func(p *StreamResourceModel) { p.Query = types.StringNull() }, upgradeRequiresReplace},
+		{"duplicate column", func(p *StreamResourceModel) {
+			p.Query = types.StringValue("SELECT ID, AMOUNT, AMOUNT * 2 AS ID FROM ORDERS WHERE AMOUNT > 0 EMIT CHANGES")
+		}, upgradeInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := emptyStream("ORDERS_FILTERED")
+			state.KafkaTopic = types.StringValue("orders_filtered")
+			state.ValueFormat = types.StringValue("JSON")
+			state.Query = types.StringValue(query)
+
+			plan := state
+			test.change(&plan)
+
+			check := checkUpgrade(state, plan)
+			if check.compatibility != test.want {
+				t.Errorf("expected compatibility %d, got %d: %v", test.want, check.compatibility, check.reasons)
+			}
+			if test.want == upgradeRequiresReplace && len(check.paths) == 0 {
+				t.Error("expected paths which require a replacement")
+			}
+		})
+	}
+}
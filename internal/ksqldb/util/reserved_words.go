@@ -0,0 +1,24 @@
+package util
+
+import (
+	"slices"
+	"strings"
+)
+
+// reservedWords are the keywords of the ksqlDB grammar which can't be used as unquoted identifiers.
+// See https://docs.ksqldb.io/en/latest/reference/sql/syntax/lexical-structure/#keywords
+var reservedWords = []string{
+	"ADVANCE", "ALL", "AND", "AS", "AT", "BEGINNING", "BETWEEN", "BY", "CASE", "CAST",
+	"CONNECTOR", "CONNECTORS", "CREATE", "DEFINE", "DELETE", "DESCRIBE", "DISTINCT", "DROP", "ELSE", "END",
+	"EXISTS", "EXTENDED", "FALSE", "FROM", "FULL", "GROUP", "HAVING", "HOPPING", "IN", "INNER",
+	"INSERT", "INTO", "IS", "JOIN", "LEFT", "LIKE", "LIMIT", "LIST", "LOAD", "NOT",
+	"NULL", "ON", "OR", "OUTER", "PAUSE", "PRINT", "PROPERTIES", "QUERIES", "QUERY", "RENAME",
+	"RESUME", "RETENTION", "RIGHT", "RUN", "SCRIPT", "SELECT", "SIZE", "STREAM", "STREAMS", "TABLE",
+	"TERMINATE", "THEN", "TO", "TOPIC", "TOPICS", "TRUE", "TUMBLING", "UNDEFINE", "VALUES", "WHEN",
+	"WHERE", "WINDOW", "WITH", "WITHIN",
+}
+
+// IsReservedWord reports whether the given unquoted identifier is a reserved word in ksqlDB.
+func IsReservedWord(v string) bool {
+	return slices.Contains(reservedWords, strings.ToUpper(v))
+}
@@ -1,5 +1,31 @@
 package util
 
+import (
+	"regexp"
+	"strings"
+)
+
+var unquotedIdentifierPattern = regexp.MustCompile(`^[A-Z0-9_]+$`)
+
 func IsBackTicked(v string) bool {
-	return v[0] == '`' && v[len(v)-1] == '`'
+	return len(v) >= 2 && v[0] == '`' && v[len(v)-1] == '`'
+}
+
+// NormalizeIdentifier returns the identifier the way ksqlDB stores it: back ticked identifiers keep their
+// case but lose the backticks, all others are converted to uppercase.
+func NormalizeIdentifier(v string) string {
+	if IsBackTicked(v) {
+		return v[1 : len(v)-1]
+	}
+	return strings.ToUpper(v)
+}
+
+// QuoteIdentifier returns the name of an object as reported by ksqlDB in the form it must be written in
+// statements: enclosed by backticks if it contains characters other than uppercase letters, numbers or
+// underscore or if it is a reserved word.
+func QuoteIdentifier(name string) string {
+	if !unquotedIdentifierPattern.MatchString(name) || IsReservedWord(name) {
+		return "`" + name + "`"
+	}
+	return name
 }
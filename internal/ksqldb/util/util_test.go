@@ -0,0 +1,84 @@
+package util
+
+import "testing"
+
+func TestIsBackTicked(t *testing.T) {
+
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"`orders`", true},
+		{"``", true},
+		{"`", false},
+		{"", false},
+		{"ORDERS", false},
+		{"`ORDERS", false},
+	}
+
+	for _, test := range tests {
+		if got := IsBackTicked(test.value); got != test.want {
+			t.Errorf("%q: expected %t, got %t", test.value, test.want, got)
+		}
+	}
+}
+
+func TestNormalizeIdentifier(t *testing.T) {
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"orders", "ORDERS"},
+		{"Orders_v2", "ORDERS_V2"},
+		{"`Orders`", "Orders"},
+		{"``", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if got := NormalizeIdentifier(test.value); got != test.want {
+			t.Errorf("%q: expected %q, got %q", test.value, test.want, got)
+		}
+	}
+}
+
+func TestIsReservedWord(t *testing.T) {
+
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"SELECT", true},
+		{"stream", true},
+		{"Within", true},
+		{"ORDERS", false},
+		{"SELECTED", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if got := IsReservedWord(test.value); got != test.want {
+			t.Errorf("%q: expected %t, got %t", test.value, test.want, got)
+		}
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"ORDERS_V2", "ORDERS_V2"},
+		{"Orders", "`Orders`"},
+		{"my orders", "`my orders`"},
+		{"SELECT", "`SELECT`"},
+	}
+
+	for _, test := range tests {
+		if got := QuoteIdentifier(test.name); got != test.want {
+			t.Errorf("%q: expected %q, got %q", test.name, test.want, got)
+		}
+	}
+}
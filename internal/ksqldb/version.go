@@ -0,0 +1,164 @@
+package ksqldb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"regexp"
+	"strconv"
+)
+
+// ServerVersion is the version of the ksqlDB server. Confluent Platform releases are mapped to the ksqlDB
+// version they ship with.
+type ServerVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+var versionPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// confluentPlatformVersions maps Confluent Platform releases to the ksqlDB release they are based on.
+var confluentPlatformVersions = map[ServerVersion]ServerVersion{
+	{Major: 6, Minor: 0}: {Major: 0, Minor: 10},
+	{Major: 6, Minor: 1}: {Major: 0, Minor: 15},
+	{Major: 6, Minor: 2}: {Major: 0, Minor: 17},
+	{Major: 7, Minor: 0}: {Major: 0, Minor: 21},
+	{Major: 7, Minor: 1}: {Major: 0, Minor: 23},
+	{Major: 7, Minor: 2}: {Major: 0, Minor: 26},
+	{Major: 7, Minor: 3}: {Major: 0, Minor: 28},
+	{Major: 7, Minor: 4}: {Major: 0, Minor: 29},
+	{Major: 7, Minor: 5}: {Major: 0, Minor: 29},
+}
+
+// latestConfluentPlatformVersion is assumed for Confluent Platform releases newer than the known ones.
+var latestConfluentPlatformVersion = ServerVersion{Major: 0, Minor: 29}
+
+// parseServerVersion parses the version reported by /info, e.g. "0.29.0" or "7.4.1-ce".
+func parseServerVersion(v string) (ServerVersion, error) {
+
+	match := versionPattern.FindStringSubmatch(v)
+	if match == nil {
+		return ServerVersion{}, fmt.Errorf("unsupported ksqlDB version '%s'", v)
+	}
+
+	major, _ := strconv.Atoi(match[1])
+	minor, _ := strconv.Atoi(match[2])
+	patch, _ := strconv.Atoi(match[3])
+
+	version := ServerVersion{Major: major, Minor: minor, Patch: patch}
+
+	// ksqlDB itself never left 0.x, so anything else is a Confluent Platform release
+	if major == 0 {
+		return version, nil
+	}
+
+	if mapped, ok := confluentPlatformVersions[ServerVersion{Major: major, Minor: minor}]; ok {
+		return mapped, nil
+	}
+
+	if major > 7 || (major == 7 && minor > 5) {
+		return latestConfluentPlatformVersion, nil
+	}
+
+	return ServerVersion{}, fmt.Errorf("unsupported Confluent Platform version '%s'", v)
+}
+
+func (v ServerVersion) AtLeast(other ServerVersion) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor > other.Minor
+	}
+	return v.Patch >= other.Patch
+}
+
+func (v ServerVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// feature is a part of a statement which is only supported by newer ksqlDB versions.
+type feature struct {
+	description string
+	since       ServerVersion
+}
+
+func (f feature) String() string {
+	return fmt.Sprintf("%s requires ksqlDB >= %d.%d", f.description, f.since.Major, f.since.Minor)
+}
+
+var (
+	featureCreateOrReplace = feature{"CREATE OR REPLACE", ServerVersion{Major: 0, Minor: 12}}
+	featureKeyFormat       = feature{"key_format", ServerVersion{Major: 0, Minor: 15}}
+	featureSourceStream    = feature{"source", ServerVersion{Major: 0, Minor: 22}}
+	featureKeySchemaId     = feature{"key_schema_id", ServerVersion{Major: 0, Minor: 24}}
+	featureValueSchemaId   = feature{"value_schema_id", ServerVersion{Major: 0, Minor: 24}}
+	featureRetention       = feature{"retention_ms", ServerVersion{Major: 0, Minor: 28}}
+)
+
+// checkStreamFeatures returns an error diagnostic for each attribute of the stream which the given ksqlDB
+// version doesn't support.
+func checkStreamFeatures(version ServerVersion, data StreamResourceModel) diag.Diagnostics {
+
+	var diags diag.Diagnostics
+
+	attributes := []struct {
+		attribute string
+		value     attr.Value
+		feature   feature
+	}{
+		{"key_format", data.KeyFormat, featureKeyFormat},
+		{"key_schema_id", data.KeySchemaId, featureKeySchemaId},
+		{"value_schema_id", data.ValueSchemaId, featureValueSchemaId},
+		{"retention_ms", data.Retention, featureRetention},
+	}
+
+	for _, a := range attributes {
+		if a.value.IsNull() || a.value.IsUnknown() || version.AtLeast(a.feature.since) {
+			continue
+		}
+		diags.AddAttributeError(path.Root(a.attribute), "Unsupported ksqlDB Version",
+			fmt.Sprintf("%s, but the server runs ksqlDB %s.", a.feature, version))
+	}
+
+	if data.Source.ValueBool() && !version.AtLeast(featureSourceStream.since) {
+		diags.AddAttributeError(path.Root("source"), "Unsupported ksqlDB Version",
+			fmt.Sprintf("%s, but the server runs ksqlDB %s.", featureSourceStream, version))
+	}
+
+	// streams which aren't source streams are always created with CREATE OR REPLACE
+	if !data.Source.ValueBool() && !version.AtLeast(featureCreateOrReplace.since) {
+		diags.AddError("Unsupported ksqlDB Version",
+			fmt.Sprintf("%s, but the server runs ksqlDB %s.", featureCreateOrReplace, version))
+	}
+
+	return diags
+}
+
+// serverVersion returns the version of the ksqlDB server. It is fetched from /info on first use and cached.
+func (c *Client) serverVersion(ctx context.Context) (ServerVersion, error) {
+
+	c.versionMutex.Lock()
+	defer c.versionMutex.Unlock()
+
+	if c.version != nil {
+		return *c.version, nil
+	}
+
+	info, err := c.serverInfo(ctx)
+	if err != nil {
+		return ServerVersion{}, err
+	}
+
+	version, err := parseServerVersion(info.Version)
+	if err != nil {
+		return ServerVersion{}, err
+	}
+
+	c.version = &version
+
+	return version, nil
+}
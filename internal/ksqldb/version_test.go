@@ -0,0 +1,124 @@
+package ksqldb
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"testing"
+)
+
+func TestParseServerVersion(t *testing.T) {
+
+	tests := []struct {
+		version string
+		want    ServerVersion
+	}{
+		{"0.29.0", ServerVersion{0, 29, 0}},
+		{"0.15.0-rc863", ServerVersion{0, 15, 0}},
+		{"v0.28.2", ServerVersion{0, 28, 2}},
+		{"7.1.0", ServerVersion{0, 23, 0}},
+		{"7.4.1-ce", ServerVersion{0, 29, 0}},
+		{"6.2.12", ServerVersion{0, 17, 0}},
+		{"7.9.0", ServerVersion{0, 29, 0}},
+	}
+
+	for _, test := range tests {
+		got, err := parseServerVersion(test.version)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.version, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: expected %s, got %s", test.version, test.want, got)
+		}
+	}
+
+	for _, version := range []string{"", "latest", "5.5.0"} {
+		if _, err := parseServerVersion(version); err == nil {
+			t.Errorf("%s: expected an error", version)
+		}
+	}
+}
+
+func TestServerVersionAtLeast(t *testing.T) {
+
+	tests := []struct {
+		version ServerVersion
+		other   ServerVersion
+		want    bool
+	}{
+		{ServerVersion{0, 29, 0}, ServerVersion{0, 24, 0}, true},
+		{ServerVersion{0, 24, 0}, ServerVersion{0, 24, 0}, true},
+		{ServerVersion{0, 24, 1}, ServerVersion{0, 24, 2}, false},
+		{ServerVersion{0, 23, 9}, ServerVersion{0, 24, 0}, false},
+		{ServerVersion{1, 0, 0}, ServerVersion{0, 29, 0}, true},
+	}
+
+	for _, test := range tests {
+		if got := test.version.AtLeast(test.other); got != test.want {
+			t.Errorf("%s >= %s: expected %t, got %t", test.version, test.other, test.want, got)
+		}
+	}
+}
+
+func TestCheckStreamFeatures(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		version ServerVersion
+		change  func(data *StreamResourceModel)
+		// want are the attributes which are reported, an empty path for the whole stream
+		want []string
+	}{
+		{"plain stream", ServerVersion{0, 12, 0}, func(d *StreamResourceModel) {}, nil},
+		{"before CREATE OR REPLACE", ServerVersion{0, 11, 0}, func(d *StreamResourceModel) {}, []string{""}},
+		{"key format", ServerVersion{0, 14, 0}, func(d *StreamResourceModel) { d.KeyFormat = types.StringValue("AVRO") }, []string{"key_format"}},
+		{"unknown key format", ServerVersion{0, 14, 0}, func(d *StreamResourceModel) { d.KeyFormat = types.StringUnknown() }, nil},
+		{"schema IDs", ServerVersion{0, 23, 0}, func(d *StreamResourceModel) {
+			d.KeySchemaId = types.Int64Value(1)
+			d.ValueSchemaId = types.Int64Value(2)
+		}, []string{"key_schema_id", "value_schema_id"}},
+		{"schema IDs supported", ServerVersion{0, 24, 0}, func(d *StreamResourceModel) { d.ValueSchemaId = types.Int64Value(2) }, nil},
+		{"retention", ServerVersion{0, 27, 0}, func(d *StreamResourceModel) { d.Retention = types.Int64Value(1000) }, []string{"retention_ms"}},
+		{"source stream", ServerVersion{0, 21, 0}, func(d *StreamResourceModel) { d.Source = types.BoolValue(true) }, []string{"source"}},
+		{"old source stream", ServerVersion{0, 11, 0}, func(d *StreamResourceModel) { d.Source = types.BoolValue(true) }, []string{"source"}},
+	}
+
+	for _, test := range tests {
+		data := emptyStream("ORDERS")
+		test.change(&data)
+
+		var got []string
+		for _, d := range checkStreamFeatures(test.version, data) {
+			attribute := ""
+			if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+				attribute = withPath.Path().String()
+			}
+			got = append(got, attribute)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: expected %q, got %q", test.name, test.want, got)
+		}
+	}
+}
+
+// The version is fetched once per client, not for every planned stream.
+func TestClientServerVersion(t *testing.T) {
+	ctx := context.Background()
+	server := testAccFakeServer(t)
+	server.SetVersion("7.3.2")
+	client := NewClusterClient([]string{server.URL}, "", "")
+
+	version, err := client.serverVersion(ctx)
+	if err != nil || version != (ServerVersion{0, 28, 0}) {
+		t.Fatalf("expected 0.28.0, got %s: %v", version, err)
+	}
+
+	server.SetVersion("0.29.0")
+
+	if version, _ := client.serverVersion(ctx); version != (ServerVersion{0, 28, 0}) {
+		t.Errorf("expected the cached version 0.28.0, got %s", version)
+	}
+}
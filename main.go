@@ -7,6 +7,7 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"terraform-provider-ksqldb/internal/ksqldb"
@@ -33,10 +34,30 @@ var (
 
 func main() {
 	var debug bool
+	var generateImports bool
+	var importMigrations string
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	flag.BoolVar(&generateImports, "generate-imports", false, "set to true to print resource and import blocks for all objects of the ksqlDB server "+
+		"configured by the KSQLDB_URL, KSQLDB_USERNAME and KSQLDB_PASSWORD environment variables")
+	flag.StringVar(&importMigrations, "import-migrations", "", "set to a ksql-migrations directory to print resource and import blocks for all "+
+		"objects which exist after applying its migrations")
 	flag.Parse()
 
+	if importMigrations != "" {
+		if err := ksqldb.GenerateImportsFromMigrations(importMigrations, os.Stdout); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
+	if generateImports {
+		if err := ksqldb.GenerateImports(context.Background(), version, os.Stdout); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	opts := providerserver.ServeOpts{
 		Address: "registry.terraform.io/shmyer/ksqldb",
 		Debug:   debug,